@@ -7,15 +7,25 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
-	"github.com/yourusername/flex/media_server/internal/config"
+	"github.com/yourusername/flex/media_server/internal/api/middleware"
 	"github.com/yourusername/flex/media_server/internal/api/routes"
+	"github.com/yourusername/flex/media_server/internal/config"
+	"github.com/yourusername/flex/media_server/internal/housekeeping"
+	"github.com/yourusername/flex/media_server/internal/media/indexer"
+	"github.com/yourusername/flex/media_server/internal/media/watcher"
+	"github.com/yourusername/flex/media_server/internal/metadata"
+	tlsserver "github.com/yourusername/flex/media_server/internal/server"
+	"github.com/yourusername/flex/media_server/internal/worker"
 	"github.com/yourusername/flex/media_server/pkg/database"
 )
 
@@ -26,7 +36,7 @@ func main() {
 	}
 
 	// Initialize logger
-	logger, err := initLogger()
+	logger, logLevel, err := initLogger()
 	if err != nil {
 		log.Fatal("Failed to initialize logger:", err)
 	}
@@ -55,6 +65,81 @@ func main() {
 		logger.Fatal("Failed to connect to Redis", zap.Error(err))
 	}
 
+	// Background subsystems (media watcher, workers, ...) share this
+	// context and are canceled before the HTTP servers are shut down.
+	bgCtx, cancelBg := context.WithCancel(context.Background())
+	defer cancelBg()
+
+	if cfg.Media.WatchEnabled {
+		// TODO: pass a StateLister backed by the media repository once it
+		// exists, so the hourly reconcile pass can run.
+		mediaWatcher, err := watcher.New(cfg.Media, redisClient, nil, logger)
+		if err != nil {
+			logger.Fatal("Failed to create media watcher", zap.Error(err))
+		}
+		if err := mediaWatcher.Start(bgCtx); err != nil {
+			logger.Fatal("Failed to start media watcher", zap.Error(err))
+		}
+		defer mediaWatcher.Close()
+	}
+
+	// Job queue and worker pool for transcoding, thumbnail, and metadata
+	// tasks, so the HTTP path only ever enqueues work.
+	jobQueue, err := worker.NewRedisQueue(bgCtx, redisClient)
+	if err != nil {
+		logger.Fatal("Failed to create job queue", zap.Error(err))
+	}
+
+	if cfg.Media.WatchEnabled {
+		// Drains flex:media:events (populated by the watcher above) into
+		// jobQueue, so a filesystem change actually results in queued
+		// work instead of just sitting in the stream.
+		mediaIndexer := indexer.NewBridge(redisClient, jobQueue, logger)
+		if err := mediaIndexer.Start(bgCtx); err != nil {
+			logger.Fatal("Failed to start media indexer bridge", zap.Error(err))
+		}
+	}
+
+	// Metadata provider chain (TMDB, OMDB, local-filename fallback),
+	// each wrapped in its own circuit breaker and rate limiter. Held
+	// behind a chainHolder so config.Watch can swap in a freshly built
+	// Chain when external.* changes, without restarting the process.
+	chainHolder := newChainHolder(metadata.NewChain(cfg.External, redisClient, logger))
+
+	// Reconfigure in place when config.yaml changes, without a restart.
+	// Only the pieces that are actually wired to observe a change are
+	// applied here: the log level (via the AtomicLevel logLevel shares
+	// with the logger) and the metadata provider chain (rebuilt from
+	// the new external.* section). Media and database settings still
+	// require a restart to take effect.
+	config.Watch(func(next *config.Config) {
+		logger.Info("Configuration reloaded", zap.String("logging.level", next.Logging.Level))
+
+		if lvl, err := zapcore.ParseLevel(next.Logging.Level); err == nil {
+			logLevel.SetLevel(lvl)
+		} else {
+			logger.Warn("Ignoring invalid logging.level from reloaded config", zap.String("level", next.Logging.Level))
+		}
+
+		chainHolder.Set(metadata.NewChain(next.External, redisClient, logger))
+	})
+
+	workerPool := worker.NewPool(jobQueue, cfg.Worker.Concurrency, cfg.Worker.MaxRetries, logger)
+	// TODO: register real handlers once the ffmpeg/MediaInfo probe
+	// package exists; these stubs keep the queue draining.
+	workerPool.Register(worker.JobProbeMedia, stubHandler(logger))
+	workerPool.Register(worker.JobGeneratePoster, stubHandler(logger))
+	workerPool.Register(worker.JobGenerateThumbnails, stubHandler(logger))
+	workerPool.Register(worker.JobFetchTMDBMetadata, fetchMetadataHandler(chainHolder, logger))
+	workerPool.Register(worker.JobTranscode, stubHandler(logger))
+	workerPool.Register(worker.JobRemoveMedia, stubHandler(logger))
+	workerPool.Start(bgCtx)
+
+	// Periodic dedupe/orphan-prune/taxonomy-compact passes over the
+	// media library.
+	housekeepingScheduler := housekeeping.New(db, cfg.Housekeeping, cfg.Media, logger)
+	housekeepingScheduler.Start(bgCtx)
+
 	// Set Gin mode
 	if cfg.App.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -62,24 +147,47 @@ func main() {
 
 	// Create Gin router
 	router := gin.New()
-	
+
 	// Add middleware
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
 	// Setup routes
 	routes.SetupRoutes(router, db, redisClient, cfg, logger)
+	router.GET("/internal/workers/stats", workerPool.StatsHandler())
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	admin := router.Group("/admin", middleware.RequireAuth(cfg.JWT), middleware.RequireRole("admin"))
+	admin.POST("/housekeeping/run", housekeepingScheduler.AdminRunHandler())
+
+	// TLS is optional: a cert/key pair or ACME domains turn on an HTTPS
+	// listener on cfg.App.TLSPort, in addition to the plaintext one.
+	tlsCfg, err := tlsserver.BuildTLSConfig(cfg.App, cfg.Media)
+	if err != nil {
+		logger.Fatal("Failed to build TLS configuration", zap.Error(err))
+	}
+
+	// The plaintext server either serves the API directly, or, once TLS
+	// is configured and app.https_redirect is set, just 301-redirects to
+	// the HTTPS listener.
+	plaintextHandler := http.Handler(router)
+	if tlsCfg != nil && cfg.App.HTTPSRedirect {
+		plaintextHandler = tlsserver.RedirectHandler(cfg.App.TLSPort)
+	}
+	if tlsCfg != nil && tlsCfg.ACMEManager != nil {
+		// ACME's HTTP-01 challenge must reach the manager over plaintext
+		// HTTP, so it has to sit in front of (not behind) the redirect.
+		plaintextHandler = tlsCfg.ACMEManager.HTTPHandler(plaintextHandler)
+	}
 
-	// Create HTTP server
-	server := &http.Server{
+	httpServer := &http.Server{
 		Addr:           fmt.Sprintf("%s:%s", cfg.App.Host, cfg.App.Port),
-		Handler:        router,
+		Handler:        plaintextHandler,
 		ReadTimeout:    30 * time.Second,
 		WriteTimeout:   30 * time.Second,
 		MaxHeaderBytes: 1 << 20, // 1 MB
 	}
 
-	// Start server in goroutine
 	go func() {
 		logger.Info("Starting Flex Media Server",
 			zap.String("host", cfg.App.Host),
@@ -87,31 +195,142 @@ func main() {
 			zap.String("environment", cfg.App.Environment),
 		)
 
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.Fatal("Failed to start server", zap.Error(err))
 		}
 	}()
 
+	var httpsServer *http.Server
+	if tlsCfg != nil {
+		if tlsCfg.Reloader != nil {
+			go tlsCfg.Reloader.Watch(bgCtx.Done())
+		}
+
+		httpsServer = &http.Server{
+			Addr:           fmt.Sprintf("%s:%s", cfg.App.Host, cfg.App.TLSPort),
+			Handler:        router,
+			TLSConfig:      tlsCfg.Config,
+			ReadTimeout:    30 * time.Second,
+			WriteTimeout:   30 * time.Second,
+			MaxHeaderBytes: 1 << 20,
+		}
+
+		go func() {
+			logger.Info("Starting Flex Media Server (TLS)", zap.String("port", cfg.App.TLSPort))
+
+			if err := httpsServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				logger.Fatal("Failed to start HTTPS server", zap.Error(err))
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logger.Info("Shutting down server...")
+	cancelBg()
 
 	// Graceful shutdown with 30 second timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
+	if err := httpServer.Shutdown(ctx); err != nil {
 		logger.Fatal("Server forced to shutdown", zap.Error(err))
 	}
 
+	if httpsServer != nil {
+		if err := httpsServer.Shutdown(ctx); err != nil {
+			logger.Fatal("HTTPS server forced to shutdown", zap.Error(err))
+		}
+	}
+
+	// Drain in-flight jobs on their own timeout, separate from the HTTP
+	// server's, since a transcode can legitimately outlive 30 seconds.
+	workerCtx, workerCancel := context.WithTimeout(context.Background(), cfg.Worker.ShutdownTimeout)
+	defer workerCancel()
+
+	if err := workerPool.Shutdown(workerCtx); err != nil {
+		logger.Warn("Worker pool did not fully drain", zap.Error(err))
+	}
+
 	logger.Info("Server shutdown complete")
 }
 
-// initLogger initializes the application logger
-func initLogger() (*zap.Logger, error) {
+// chainHolder lets config.Watch swap in a metadata.Chain rebuilt from a
+// reloaded external.* config section, while fetchMetadataHandler (running
+// concurrently on worker goroutines) always reads the current one.
+type chainHolder struct {
+	mu    sync.RWMutex
+	chain *metadata.Chain
+}
+
+func newChainHolder(chain *metadata.Chain) *chainHolder {
+	return &chainHolder{chain: chain}
+}
+
+func (h *chainHolder) Get() *metadata.Chain {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.chain
+}
+
+func (h *chainHolder) Set(chain *metadata.Chain) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.chain = chain
+}
+
+// fetchMetadataHandler looks up and logs merged metadata for a job's
+// media_id/title. Persisting the result onto the media row is left as a
+// TODO until the media repository package exists.
+func fetchMetadataHandler(holder *chainHolder, logger *zap.Logger) worker.Handler {
+	return func(ctx context.Context, job worker.Job) error {
+		title, _ := job.Payload["title"].(string)
+		mediaID, _ := job.Payload["media_id"].(string)
+
+		chain := holder.Get()
+		ids, err := chain.ResolveIDs(ctx, title, 0)
+		if err != nil {
+			return fmt.Errorf("resolving provider IDs for %q: %w", title, err)
+		}
+
+		details, err := chain.FetchDetails(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("fetching details for %q: %w", title, err)
+		}
+
+		logger.Info("Fetched metadata",
+			zap.String("media_id", mediaID),
+			zap.String("title", details.Title),
+			zap.String("release_date", details.ReleaseDate),
+		)
+		// TODO: persist details onto the media row once the repository
+		// package exists.
+		return nil
+	}
+}
+
+// stubHandler logs receipt of a job whose real processing logic has not
+// been implemented yet, so registering it keeps the queue draining
+// instead of dead-lettering every job of that type.
+func stubHandler(logger *zap.Logger) worker.Handler {
+	return func(ctx context.Context, job worker.Job) error {
+		logger.Info("Received job (stub handler)",
+			zap.String("job_id", job.ID),
+			zap.String("job_type", string(job.Type)),
+		)
+		return nil
+	}
+}
+
+// initLogger initializes the application logger. The returned
+// zap.AtomicLevel is shared with the logger's core, so calling
+// SetLevel on it changes what the already-built logger emits, which is
+// what lets config.Watch apply a reloaded logging.level without
+// rebuilding the logger.
+func initLogger() (*zap.Logger, zap.AtomicLevel, error) {
 	env := os.Getenv("ENV")
 	logFormat := os.Getenv("LOG_FORMAT")
 
@@ -145,5 +364,6 @@ func initLogger() (*zap.Logger, error) {
 		config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
 	}
 
-	return config.Build()
-}
\ No newline at end of file
+	logger, err := config.Build()
+	return logger, config.Level, err
+}
@@ -0,0 +1,64 @@
+// Package middleware holds Gin middleware shared across route groups:
+// JWT authentication and role enforcement.
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/yourusername/flex/media_server/internal/config"
+)
+
+// claims is the JWT payload Flex issues on login.
+type claims struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+const (
+	contextKeyUserID = "user_id"
+	contextKeyRole   = "role"
+)
+
+// RequireAuth validates the Bearer token in the Authorization header
+// against cfg.Secret and stores the user ID and role in the request
+// context for downstream handlers (and RequireRole).
+func RequireAuth(cfg config.JWTConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token := strings.TrimPrefix(header, "Bearer ")
+		if token == "" || token == header {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+			return
+		}
+
+		parsed, err := jwt.ParseWithClaims(token, &claims{}, func(t *jwt.Token) (interface{}, error) {
+			return []byte(cfg.Secret), nil
+		})
+		if err != nil || !parsed.Valid {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+			return
+		}
+
+		parsedClaims := parsed.Claims.(*claims)
+		c.Set(contextKeyUserID, parsedClaims.UserID)
+		c.Set(contextKeyRole, parsedClaims.Role)
+		c.Next()
+	}
+}
+
+// RequireRole rejects requests whose token role (set by RequireAuth)
+// does not match role. Mount after RequireAuth.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if actual, _ := c.Get(contextKeyRole); actual != role {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			return
+		}
+		c.Next()
+	}
+}
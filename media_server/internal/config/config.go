@@ -1,21 +1,28 @@
 package config
 
 import (
-	"os"
-	"strconv"
+	"fmt"
+	"reflect"
 	"strings"
 	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	App      AppConfig      `mapstructure:"app"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	Media    MediaConfig    `mapstructure:"media"`
-	External ExternalConfig `mapstructure:"external"`
-	Logging  LoggingConfig  `mapstructure:"logging"`
+	App          AppConfig          `mapstructure:"app"`
+	Database     DatabaseConfig     `mapstructure:"database"`
+	Redis        RedisConfig        `mapstructure:"redis"`
+	JWT          JWTConfig          `mapstructure:"jwt"`
+	Media        MediaConfig        `mapstructure:"media"`
+	External     ExternalConfig     `mapstructure:"external"`
+	Logging      LoggingConfig      `mapstructure:"logging"`
+	Worker       WorkerConfig       `mapstructure:"worker"`
+	Housekeeping HousekeepingConfig `mapstructure:"housekeeping"`
 }
 
 // AppConfig holds application-specific configuration
@@ -25,18 +32,24 @@ type AppConfig struct {
 	Host        string   `mapstructure:"host"`
 	Port        string   `mapstructure:"port"`
 	Origins     []string `mapstructure:"allowed_origins"`
+
+	TLSCertFile   string   `mapstructure:"tls_cert_file"`
+	TLSKeyFile    string   `mapstructure:"tls_key_file"`
+	TLSPort       string   `mapstructure:"tls_port"`
+	HTTPSRedirect bool     `mapstructure:"https_redirect"`
+	ACMEDomains   []string `mapstructure:"acme_domains"`
 }
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
-	Host            string        `mapstructure:"host"`
-	Port            string        `mapstructure:"port"`
-	User            string        `mapstructure:"user"`
-	Password        string        `mapstructure:"password"`
-	Name            string        `mapstructure:"name"`
-	SSLMode         string        `mapstructure:"sslmode"`
-	MaxConnections  int           `mapstructure:"max_connections"`
-	MaxIdleTime     time.Duration `mapstructure:"max_idle_time"`
+	Host           string        `mapstructure:"host"`
+	Port           string        `mapstructure:"port"`
+	User           string        `mapstructure:"user"`
+	Password       string        `mapstructure:"password"`
+	Name           string        `mapstructure:"name"`
+	SSLMode        string        `mapstructure:"sslmode"`
+	MaxConnections int           `mapstructure:"max_connections"`
+	MaxIdleTime    time.Duration `mapstructure:"max_idle_time"`
 }
 
 // RedisConfig holds Redis configuration
@@ -55,18 +68,34 @@ type JWTConfig struct {
 
 // MediaConfig holds media processing configuration
 type MediaConfig struct {
-	RootPath      string `mapstructure:"root_path"`
-	UploadPath    string `mapstructure:"upload_path"`
-	PosterPath    string `mapstructure:"poster_path"`
-	ThumbnailPath string `mapstructure:"thumbnail_path"`
-	FFmpegPath    string `mapstructure:"ffmpeg_path"`
-	MediaInfoPath string `mapstructure:"mediainfo_path"`
+	RootPath          string        `mapstructure:"root_path"`
+	UploadPath        string        `mapstructure:"upload_path"`
+	PosterPath        string        `mapstructure:"poster_path"`
+	ThumbnailPath     string        `mapstructure:"thumbnail_path"`
+	FFmpegPath        string        `mapstructure:"ffmpeg_path"`
+	MediaInfoPath     string        `mapstructure:"mediainfo_path"`
+	WatchEnabled      bool          `mapstructure:"watch_enabled"`
+	WatchDebounce     time.Duration `mapstructure:"watch_debounce"`
+	ReconcileInterval time.Duration `mapstructure:"reconcile_interval"`
+	CertCachePath     string        `mapstructure:"cert_cache_path"`
 }
 
 // ExternalConfig holds external API configuration
 type ExternalConfig struct {
-	TMDBAPIKey string `mapstructure:"tmdb_api_key"`
-	OMDBAPIKey string `mapstructure:"omdb_api_key"`
+	TMDBAPIKey string           `mapstructure:"tmdb_api_key"`
+	OMDBAPIKey string           `mapstructure:"omdb_api_key"`
+	CacheTTL   time.Duration    `mapstructure:"cache_ttl"`
+	Providers  []ProviderConfig `mapstructure:"providers"` // priority order: first to last
+}
+
+// ProviderConfig tunes a single metadata provider's position and limits
+// within the metadata.Chain, without requiring a code change to adjust.
+type ProviderConfig struct {
+	Name                string        `mapstructure:"name"`
+	Enabled             bool          `mapstructure:"enabled"`
+	QPS                 float64       `mapstructure:"qps"`
+	BreakerFailureRatio float64       `mapstructure:"breaker_failure_ratio"`
+	BreakerCooldown     time.Duration `mapstructure:"breaker_cooldown"`
 }
 
 // LoggingConfig holds logging configuration
@@ -75,81 +104,230 @@ type LoggingConfig struct {
 	Format string `mapstructure:"format"`
 }
 
-// Load loads configuration from environment variables
+// WorkerConfig holds background job worker pool configuration
+type WorkerConfig struct {
+	Concurrency     int           `mapstructure:"concurrency"`
+	MaxRetries      int           `mapstructure:"max_retries"`
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+}
+
+// HousekeepingConfig holds configuration for the periodic media library
+// cleanup passes (dedupe, orphan pruning, taxonomy compaction).
+type HousekeepingConfig struct {
+	Enabled           bool          `mapstructure:"enabled"`
+	Interval          time.Duration `mapstructure:"interval"`
+	BatchSize         int           `mapstructure:"batch_size"`
+	DedupeMedia       bool          `mapstructure:"dedupe_media"`
+	PruneOrphanFiles  bool          `mapstructure:"prune_orphan_files"`
+	PruneMissingFiles bool          `mapstructure:"prune_missing_files"`
+	CompactTaxonomy   bool          `mapstructure:"compact_taxonomy"`
+}
+
+// v is the package-level Viper instance backing Load and Watch. Keeping a
+// single instance (rather than re-parsing on every call) is what lets
+// Watch react to the same config.yaml that Load read from.
+var v = viper.New()
+
+// Load builds the application configuration by layering, from lowest to
+// highest precedence: defaults, config.yaml (searched under "./",
+// "./config/", and "/etc/flex/"), environment variables prefixed with
+// "FLEX_" (with "." in keys translated to "_", e.g. FLEX_DATABASE_HOST
+// maps to database.host), and CLI flags bound via pflag. The result is
+// validated before it is returned.
 func Load() (*Config, error) {
-	config := &Config{
-		App: AppConfig{
-			Name:        getEnv("APP_NAME", "Flex Media Server"),
-			Environment: getEnv("ENV", "development"),
-			Host:        getEnv("HOST", "0.0.0.0"),
-			Port:        getEnv("PORT", "8080"),
-			Origins:     strings.Split(getEnv("ALLOWED_ORIGINS", "http://localhost:3000"), ","),
-		},
-		Database: DatabaseConfig{
-			Host:            getEnv("DB_HOST", "localhost"),
-			Port:            getEnv("DB_PORT", "5432"),
-			User:            getEnv("DB_USER", "flex_user"),
-			Password:        getEnv("DB_PASSWORD", "flex_password"),
-			Name:            getEnv("DB_NAME", "flex_dev"),
-			SSLMode:         getEnv("DB_SSLMODE", "disable"),
-			MaxConnections:  getEnvAsInt("DB_MAX_CONNECTIONS", 25),
-			MaxIdleTime:     getEnvAsDuration("DB_MAX_IDLE_TIME", 15*time.Minute),
-		},
-		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnv("REDIS_PORT", "6379"),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       getEnvAsInt("REDIS_DB", 0),
-		},
-		JWT: JWTConfig{
-			Secret:    getEnv("JWT_SECRET", "your-secret-key"),
-			ExpiresIn: getEnvAsDuration("JWT_EXPIRES_IN", 24*time.Hour),
-		},
-		Media: MediaConfig{
-			RootPath:      getEnv("MEDIA_ROOT_PATH", "/media/library"),
-			UploadPath:    getEnv("UPLOAD_PATH", "/tmp/flex-uploads"),
-			PosterPath:    getEnv("POSTER_PATH", "/tmp/flex-posters"),
-			ThumbnailPath: getEnv("THUMBNAIL_PATH", "/tmp/flex-thumbnails"),
-			FFmpegPath:    getEnv("FFMPEG_PATH", "ffmpeg"),
-			MediaInfoPath: getEnv("MEDIAINFO_PATH", "mediainfo"),
-		},
-		External: ExternalConfig{
-			TMDBAPIKey: getEnv("TMDB_API_KEY", ""),
-			OMDBAPIKey: getEnv("OMDB_API_KEY", ""),
-		},
-		Logging: LoggingConfig{
-			Level:  getEnv("LOG_LEVEL", "info"),
-			Format: getEnv("LOG_FORMAT", "console"),
-		},
+	setDefaults(v)
+
+	v.SetConfigName("config")
+	v.SetConfigType("yaml")
+	v.AddConfigPath(".")
+	v.AddConfigPath("./config")
+	v.AddConfigPath("/etc/flex/")
+
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
 	}
 
-	return config, nil
+	v.SetEnvPrefix("FLEX")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	bindFlags(v)
+
+	cfg, err := decode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
 }
 
-// getEnv gets an environment variable or returns a default value
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// Validate fails fast when required secrets or paths are missing, most
+// importantly before the application accepts traffic in production.
+func (c *Config) Validate() error {
+	if c.App.Environment == "production" && (c.JWT.Secret == "" || c.JWT.Secret == "your-secret-key") {
+		return fmt.Errorf("jwt.secret must be set to a non-default value in production")
 	}
-	return defaultValue
+	if c.Database.Host == "" || c.Database.Name == "" || c.Database.User == "" {
+		return fmt.Errorf("database.host, database.user, and database.name are required")
+	}
+	if c.Media.RootPath == "" {
+		return fmt.Errorf("media.root_path is required")
+	}
+	return nil
 }
 
-// getEnvAsInt gets an environment variable as an integer or returns a default value
-func getEnvAsInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
+// Watch enables hot-reload of config.yaml. It invokes onChange whenever
+// logging, external, or media settings change, so a caller can apply
+// whichever of those it actually has a live hook for. As of this
+// writing, cmd/main.go only applies logging.level (via its shared
+// zap.AtomicLevel) and external.* (by rebuilding the metadata.Chain);
+// a media.* change is still reported here but main.go does not yet act
+// on it, since doing so would mean restarting the watcher goroutines.
+// Database, JWT, and network binding changes are not reported at all
+// and always require a restart.
+func Watch(onChange func(*Config)) {
+	prev, err := decode(v)
+	if err != nil {
+		prev = &Config{}
+	}
+
+	v.OnConfigChange(func(_ fsnotify.Event) {
+		next, err := decode(v)
+		if err != nil {
+			return
 		}
+
+		if next.Logging != prev.Logging ||
+			!externalEqual(next.External, prev.External) ||
+			next.Media != prev.Media {
+			onChange(next)
+		}
+
+		prev = next
+	})
+	v.WatchConfig()
+}
+
+// externalEqual compares two ExternalConfig values. ExternalConfig
+// embeds a []ProviderConfig, which makes it non-comparable with ==, so
+// Watch uses this instead.
+func externalEqual(a, b ExternalConfig) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// decode unmarshals the current Viper state into a Config, preserving
+// time.Duration and comma-separated slice decoding.
+func decode(v *viper.Viper) (*Config, error) {
+	var cfg Config
+	decodeHook := mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)
+
+	if err := v.Unmarshal(&cfg, viper.DecoderConfigOption(func(dc *mapstructure.DecoderConfig) {
+		dc.DecodeHook = decodeHook
+	})); err != nil {
+		return nil, fmt.Errorf("unmarshalling config: %w", err)
 	}
-	return defaultValue
+
+	return &cfg, nil
 }
 
-// getEnvAsDuration gets an environment variable as a duration or returns a default value
-func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
-		if duration, err := time.ParseDuration(value); err == nil {
-			return duration
-		}
+// setDefaults seeds Viper with the same defaults the old os.Getenv-based
+// loader used, so an operator with no config.yaml and no env vars still
+// gets a working development configuration.
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("app.name", "Flex Media Server")
+	v.SetDefault("app.environment", "development")
+	v.SetDefault("app.host", "0.0.0.0")
+	v.SetDefault("app.port", "8080")
+	v.SetDefault("app.allowed_origins", []string{"http://localhost:3000"})
+	v.SetDefault("app.tls_cert_file", "")
+	v.SetDefault("app.tls_key_file", "")
+	v.SetDefault("app.tls_port", "8443")
+	v.SetDefault("app.https_redirect", false)
+	v.SetDefault("app.acme_domains", []string{})
+
+	v.SetDefault("database.host", "localhost")
+	v.SetDefault("database.port", "5432")
+	v.SetDefault("database.user", "flex_user")
+	v.SetDefault("database.password", "flex_password")
+	v.SetDefault("database.name", "flex_dev")
+	v.SetDefault("database.sslmode", "disable")
+	v.SetDefault("database.max_connections", 25)
+	v.SetDefault("database.max_idle_time", "15m")
+
+	v.SetDefault("redis.host", "localhost")
+	v.SetDefault("redis.port", "6379")
+	v.SetDefault("redis.password", "")
+	v.SetDefault("redis.db", 0)
+
+	v.SetDefault("jwt.secret", "your-secret-key")
+	v.SetDefault("jwt.expires_in", "24h")
+
+	v.SetDefault("media.root_path", "/media/library")
+	v.SetDefault("media.upload_path", "/tmp/flex-uploads")
+	v.SetDefault("media.poster_path", "/tmp/flex-posters")
+	v.SetDefault("media.thumbnail_path", "/tmp/flex-thumbnails")
+	v.SetDefault("media.ffmpeg_path", "ffmpeg")
+	v.SetDefault("media.mediainfo_path", "mediainfo")
+	v.SetDefault("media.watch_enabled", false)
+	v.SetDefault("media.watch_debounce", "5s")
+	v.SetDefault("media.reconcile_interval", "1h")
+	v.SetDefault("media.cert_cache_path", "/tmp/flex-certs")
+
+	v.SetDefault("external.tmdb_api_key", "")
+	v.SetDefault("external.omdb_api_key", "")
+	v.SetDefault("external.cache_ttl", "168h")
+	v.SetDefault("external.providers", []map[string]interface{}{
+		{"name": "tmdb", "enabled": true, "qps": 4.0, "breaker_failure_ratio": 0.5, "breaker_cooldown": "30s"},
+		{"name": "omdb", "enabled": true, "qps": 1.0, "breaker_failure_ratio": 0.5, "breaker_cooldown": "30s"},
+		{"name": "local_filename", "enabled": true, "qps": 1000.0, "breaker_failure_ratio": 0.9, "breaker_cooldown": "5s"},
+	})
+
+	v.SetDefault("logging.level", "info")
+	v.SetDefault("logging.format", "console")
+
+	v.SetDefault("worker.concurrency", 4)
+	v.SetDefault("worker.max_retries", 5)
+	v.SetDefault("worker.shutdown_timeout", "45s")
+
+	// WORKER_CONCURRENCY and TLS_ACME_DOMAINS are bare (non-FLEX_-prefixed)
+	// aliases kept for operators following those subsystems' original
+	// deployment docs.
+	_ = v.BindEnv("worker.concurrency", "WORKER_CONCURRENCY")
+	_ = v.BindEnv("app.acme_domains", "TLS_ACME_DOMAINS")
+
+	v.SetDefault("housekeeping.enabled", false)
+	v.SetDefault("housekeeping.interval", "24h")
+	v.SetDefault("housekeeping.batch_size", 500)
+	v.SetDefault("housekeeping.dedupe_media", true)
+	v.SetDefault("housekeeping.prune_orphan_files", true)
+	v.SetDefault("housekeeping.prune_missing_files", true)
+	v.SetDefault("housekeeping.compact_taxonomy", true)
+}
+
+// bindFlags registers the CLI flags operators are most likely to reach
+// for at startup and binds them into Viper, calling pflag.Parse() itself
+// when nothing has parsed the command line yet, so flags get the final
+// say over file and env values.
+func bindFlags(v *viper.Viper) {
+	if !pflag.Parsed() {
+		pflag.String("host", "", "application bind host (overrides app.host)")
+		pflag.String("port", "", "application bind port (overrides app.port)")
+		pflag.String("env", "", "application environment (overrides app.environment)")
+		pflag.String("log-level", "", "log level: debug, info, warn, error (overrides logging.level)")
+		pflag.Parse()
 	}
-	return defaultValue
-}
\ No newline at end of file
+
+	_ = v.BindPFlag("app.host", pflag.Lookup("host"))
+	_ = v.BindPFlag("app.port", pflag.Lookup("port"))
+	_ = v.BindPFlag("app.environment", pflag.Lookup("env"))
+	_ = v.BindPFlag("logging.level", pflag.Lookup("log-level"))
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestSetDefaultsAndDecode(t *testing.T) {
+	vip := viper.New()
+	setDefaults(vip)
+
+	cfg, err := decode(vip)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if cfg.App.Port != "8080" {
+		t.Errorf("App.Port = %q, want %q", cfg.App.Port, "8080")
+	}
+	if cfg.Media.WatchDebounce != 5*time.Second {
+		t.Errorf("Media.WatchDebounce = %v, want 5s", cfg.Media.WatchDebounce)
+	}
+	if cfg.External.CacheTTL != 168*time.Hour {
+		t.Errorf("External.CacheTTL = %v, want 168h", cfg.External.CacheTTL)
+	}
+	if len(cfg.External.Providers) != 3 {
+		t.Fatalf("External.Providers = %d entries, want 3", len(cfg.External.Providers))
+	}
+	if cfg.External.Providers[0].Name != "tmdb" || !cfg.External.Providers[0].Enabled {
+		t.Errorf("External.Providers[0] = %+v, want enabled tmdb", cfg.External.Providers[0])
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Config{
+		App:      AppConfig{Environment: "development"},
+		Database: DatabaseConfig{Host: "localhost", User: "flex_user", Name: "flex_dev"},
+		Media:    MediaConfig{RootPath: "/media/library"},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() on a complete config returned %v, want nil", err)
+	}
+
+	missingDB := valid
+	missingDB.Database.Host = ""
+	if err := missingDB.Validate(); err == nil {
+		t.Error("Validate() with no database.host = nil, want an error")
+	}
+
+	defaultSecretInProd := valid
+	defaultSecretInProd.App.Environment = "production"
+	defaultSecretInProd.JWT.Secret = "your-secret-key"
+	if err := defaultSecretInProd.Validate(); err == nil {
+		t.Error("Validate() in production with the default jwt.secret = nil, want an error")
+	}
+}
+
+func TestExternalEqual(t *testing.T) {
+	a := ExternalConfig{Providers: []ProviderConfig{{Name: "tmdb", Enabled: true}}}
+	b := ExternalConfig{Providers: []ProviderConfig{{Name: "tmdb", Enabled: true}}}
+	c := ExternalConfig{Providers: []ProviderConfig{{Name: "tmdb", Enabled: false}}}
+
+	if !externalEqual(a, b) {
+		t.Error("externalEqual(a, b) = false, want true for equal provider slices")
+	}
+	if externalEqual(a, c) {
+		t.Error("externalEqual(a, c) = true, want false for differing provider slices")
+	}
+}
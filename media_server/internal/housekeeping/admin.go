@@ -0,0 +1,28 @@
+package housekeeping
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminRunHandler triggers a single housekeeping pass on demand. Mount
+// it behind the JWT auth + admin-role middleware, e.g.:
+//
+//	admin.POST("/housekeeping/run", scheduler.AdminRunHandler())
+func (s *Scheduler) AdminRunHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		task := c.Query("task")
+		if task == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "task query parameter is required"})
+			return
+		}
+
+		if err := s.RunTask(c.Request.Context(), task); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "completed", "task": task})
+	}
+}
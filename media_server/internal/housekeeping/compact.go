@@ -0,0 +1,62 @@
+package housekeeping
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// taxonomyTable pairs a reference table with the join table that links
+// it back to media, so zero-reference rows can be found generically.
+type taxonomyTable struct {
+	table     string
+	joinTable string
+	joinKey   string
+}
+
+var taxonomyTables = []taxonomyTable{
+	{table: "tags", joinTable: "media_tags", joinKey: "tag_id"},
+	{table: "genres", joinTable: "media_genres", joinKey: "genre_id"},
+	{table: "actors", joinTable: "media_actors", joinKey: "actor_id"},
+}
+
+// compactTaxonomyBatch deletes tag/genre/actor rows that no longer have
+// any media referencing them, inside a single transaction. Each taxonomy
+// table is queried for up to batchSize rows (not a 1/3 share of it), so
+// one call can delete up to 3*batchSize rows in all: runInBatches'
+// "caught up" check compares the total deleted against batchSize, and
+// splitting the limit three ways made it stop after one round even
+// with a large backlog still pending in each table.
+func (s *Scheduler) compactTaxonomyBatch(ctx context.Context, batchSize int) (int, error) {
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return 0, fmt.Errorf("starting taxonomy compaction transaction: %w", tx.Error)
+	}
+	defer tx.Rollback()
+
+	deleted := 0
+	for _, t := range taxonomyTables {
+		var ids []uint
+		if err := tx.
+			Table(t.table).
+			Where(fmt.Sprintf("id NOT IN (SELECT %s FROM %s)", t.joinKey, t.joinTable)).
+			Limit(batchSize).
+			Pluck("id", &ids).Error; err != nil {
+			return deleted, fmt.Errorf("listing unreferenced %s: %w", t.table, err)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		if err := tx.Table(t.table).Where("id IN ?", ids).Delete(nil).Error; err != nil {
+			return deleted, fmt.Errorf("deleting unreferenced %s: %w", t.table, err)
+		}
+		deleted += len(ids)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, fmt.Errorf("committing taxonomy compaction: %w", err)
+	}
+	return deleted, nil
+}
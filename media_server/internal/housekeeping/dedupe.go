@@ -0,0 +1,79 @@
+package housekeeping
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// dedupeMediaBatch finds up to batchSize groups of media rows sharing a
+// content_hash, merges watch_history and media_tags onto the oldest
+// (canonical) row in each group, then deletes the rest. Each group is
+// merged inside its own transaction so a failure partway through never
+// leaves a partially-merged group.
+func (s *Scheduler) dedupeMediaBatch(ctx context.Context, batchSize int) (int, error) {
+	var hashes []string
+	if err := s.db.WithContext(ctx).
+		Table("media").
+		Select("content_hash").
+		Group("content_hash").
+		Having("COUNT(*) > 1").
+		Limit(batchSize).
+		Pluck("content_hash", &hashes).Error; err != nil {
+		return 0, fmt.Errorf("listing duplicate content hashes: %w", err)
+	}
+
+	merged := 0
+	for _, hash := range hashes {
+		if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return dedupeGroup(tx, hash)
+		}); err != nil {
+			return merged, fmt.Errorf("deduping content_hash %q: %w", hash, err)
+		}
+		merged++
+	}
+
+	return merged, nil
+}
+
+// dedupeGroup merges every media row sharing contentHash onto the
+// row with the smallest id, then deletes the rest.
+func dedupeGroup(tx *gorm.DB, contentHash string) error {
+	var ids []uint
+	if err := tx.Table("media").
+		Where("content_hash = ?", contentHash).
+		Order("id ASC").
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) < 2 {
+		return nil
+	}
+
+	canonical := ids[0]
+	duplicates := ids[1:]
+
+	if err := tx.Table("watch_history").
+		Where("media_id IN ?", duplicates).
+		Update("media_id", canonical).Error; err != nil {
+		return err
+	}
+
+	// media_tags is a join table; re-pointing duplicates at the canonical
+	// row can violate its (media_id, tag_id) uniqueness constraint, so
+	// fall back to ignoring rows that would collide before deleting the
+	// rest.
+	if err := tx.Exec(`
+		UPDATE media_tags SET media_id = ?
+		WHERE media_id IN ?
+		AND tag_id NOT IN (SELECT tag_id FROM media_tags WHERE media_id = ?)
+	`, canonical, duplicates, canonical).Error; err != nil {
+		return err
+	}
+	if err := tx.Table("media_tags").Where("media_id IN ?", duplicates).Delete(nil).Error; err != nil {
+		return err
+	}
+
+	return tx.Table("media").Where("id IN ?", duplicates).Delete(nil).Error
+}
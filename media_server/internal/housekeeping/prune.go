@@ -0,0 +1,173 @@
+package housekeeping
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// missingFileGraceWindow is how long a row's file must be continuously
+// missing before pruneMissingFilesBatch will delete it, so a slow
+// network mount flapping in and out doesn't race a delete of a file
+// that's still actually there.
+const missingFileGraceWindow = time.Hour
+
+// pruneOrphanFilesBatch deletes up to batchSize poster/thumbnail files
+// under MediaConfig.PosterPath/ThumbnailPath that no longer have a
+// referencing row in the media table.
+func (s *Scheduler) pruneOrphanFilesBatch(ctx context.Context, batchSize int) (int, error) {
+	var posterPaths, thumbnailPaths []string
+	if err := s.db.WithContext(ctx).
+		Table("media").
+		Where("poster_path <> ''").
+		Pluck("poster_path", &posterPaths).Error; err != nil {
+		return 0, fmt.Errorf("listing referenced poster paths: %w", err)
+	}
+	if err := s.db.WithContext(ctx).
+		Table("media").
+		Where("thumbnail_path <> ''").
+		Pluck("thumbnail_path", &thumbnailPaths).Error; err != nil {
+		return 0, fmt.Errorf("listing referenced thumbnail paths: %w", err)
+	}
+
+	referencedSet := make(map[string]struct{}, len(posterPaths)+len(thumbnailPaths))
+	for _, p := range posterPaths {
+		referencedSet[p] = struct{}{}
+	}
+	for _, p := range thumbnailPaths {
+		referencedSet[p] = struct{}{}
+	}
+
+	pruned := 0
+	for _, dir := range []string{s.media.PosterPath, s.media.ThumbnailPath} {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return pruned, fmt.Errorf("reading %s: %w", dir, err)
+		}
+
+		for _, entry := range entries {
+			if pruned >= batchSize {
+				return pruned, nil
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if _, ok := referencedSet[path]; ok {
+				continue
+			}
+
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return pruned, fmt.Errorf("removing orphan file %s: %w", path, err)
+			}
+			pruned++
+		}
+	}
+
+	return pruned, nil
+}
+
+// pruneMissingFilesBatch deletes media rows whose underlying file has
+// been continuously missing for at least missingFileGraceWindow. A row
+// whose file goes missing is first marked with missing_since (and left
+// alone); only once a later pass finds it still missing past the grace
+// window is it actually deleted. A row whose file reappears has
+// missing_since cleared. This two-phase check is what keeps a
+// transient network-mount blip from racing a legitimate delete.
+//
+// Rows are paged through via s.pruneMissingCursor rather than
+// re-querying the same Limit window every call, so a table larger than
+// one oversampled batch is still fully covered across repeated calls
+// (and repeated scheduled runs) instead of only ever examining its
+// first batchSize*4 rows.
+func (s *Scheduler) pruneMissingFilesBatch(ctx context.Context, batchSize int) (int, error) {
+	type mediaRow struct {
+		ID           uint
+		Path         string
+		MissingSince *time.Time
+	}
+
+	s.cursorMu.Lock()
+	afterID := s.pruneMissingCursor
+	s.cursorMu.Unlock()
+
+	var rows []mediaRow
+	if err := s.db.WithContext(ctx).
+		Table("media").
+		Select("id, path, missing_since").
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(batchSize * 4). // oversample since most rows will still exist
+		Find(&rows).Error; err != nil {
+		return 0, fmt.Errorf("listing media rows: %w", err)
+	}
+
+	// With no rows left past the cursor, the end of the table has been
+	// reached: nextCursor stays 0 so the next call (this run or a later
+	// scheduled one) starts over from the top. Otherwise it advances to
+	// the last row the loop below actually examines, so rows after an
+	// early break are picked up by the next call instead of skipped.
+	var nextCursor uint
+
+	now := time.Now()
+	var toDelete, toMarkMissing, toClearMissing []uint
+
+	for _, row := range rows {
+		if len(toDelete) >= batchSize {
+			break
+		}
+		nextCursor = row.ID
+
+		_, err := os.Stat(row.Path)
+		switch {
+		case err == nil:
+			if row.MissingSince != nil {
+				toClearMissing = append(toClearMissing, row.ID)
+			}
+		case os.IsNotExist(err):
+			switch {
+			case row.MissingSince == nil:
+				toMarkMissing = append(toMarkMissing, row.ID)
+			case now.Sub(*row.MissingSince) >= missingFileGraceWindow:
+				toDelete = append(toDelete, row.ID)
+			}
+		}
+		// Any other stat error (e.g. a permissions blip) is left alone.
+	}
+
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(toDelete) > 0 {
+			if err := tx.Table("media").Where("id IN ?", toDelete).Delete(nil).Error; err != nil {
+				return err
+			}
+		}
+		if len(toMarkMissing) > 0 {
+			if err := tx.Table("media").Where("id IN ?", toMarkMissing).Update("missing_since", now).Error; err != nil {
+				return err
+			}
+		}
+		if len(toClearMissing) > 0 {
+			if err := tx.Table("media").Where("id IN ?", toClearMissing).Update("missing_since", nil).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return 0, fmt.Errorf("updating rows with missing/reappeared files: %w", err)
+	}
+
+	s.cursorMu.Lock()
+	s.pruneMissingCursor = nextCursor
+	s.cursorMu.Unlock()
+
+	return len(toDelete), nil
+}
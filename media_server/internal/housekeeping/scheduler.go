@@ -0,0 +1,177 @@
+// Package housekeeping runs periodic cleanup passes over the media
+// library: deduping rows that share a content hash, pruning poster and
+// thumbnail files and database rows that no longer have a counterpart,
+// and compacting taxonomy tables (tags, genres, actors) with no
+// remaining references.
+package housekeeping
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/yourusername/flex/media_server/internal/config"
+)
+
+// Task names accepted by RunTask and the admin endpoint.
+const (
+	TaskDedupeMedia       = "dedupe_media"
+	TaskPruneOrphanFiles  = "prune_orphan_files"
+	TaskPruneMissingFiles = "prune_missing_files"
+	TaskCompactTaxonomy   = "compact_taxonomy"
+)
+
+// passSummary is logged after every pass, and batched passes accumulate
+// it across batches before logging once.
+type passSummary struct {
+	BatchesRun int
+	Affected   int
+}
+
+// Scheduler runs the configured housekeeping passes on a timer, and
+// exposes RunTask for the on-demand admin endpoint.
+type Scheduler struct {
+	db     *gorm.DB
+	cfg    config.HousekeepingConfig
+	media  config.MediaConfig
+	logger *zap.Logger
+
+	// cursorMu guards pruneMissingCursor, which pages pruneMissingFilesBatch
+	// through the media table across calls instead of rescanning the same
+	// window every time. A scheduled run and an admin-triggered RunTask
+	// could otherwise race on it.
+	cursorMu           sync.Mutex
+	pruneMissingCursor uint
+}
+
+// New creates a Scheduler. db is expected to be a *gorm.DB connected to
+// the application database.
+func New(db *gorm.DB, cfg config.HousekeepingConfig, media config.MediaConfig, logger *zap.Logger) *Scheduler {
+	return &Scheduler{db: db, cfg: cfg, media: media, logger: logger}
+}
+
+// Start launches the periodic scheduler loop. It is a no-op if
+// housekeeping is disabled in config. The loop stops when ctx is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	if !s.cfg.Enabled {
+		s.logger.Info("Housekeeping disabled, skipping scheduler")
+		return
+	}
+
+	interval := s.cfg.Interval
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.RunAll(ctx)
+			}
+		}
+	}()
+}
+
+// RunAll runs every pass that is individually enabled in config.
+func (s *Scheduler) RunAll(ctx context.Context) {
+	for _, task := range []string{TaskDedupeMedia, TaskPruneOrphanFiles, TaskPruneMissingFiles, TaskCompactTaxonomy} {
+		if !s.taskEnabled(task) {
+			continue
+		}
+		if err := s.RunTask(ctx, task); err != nil {
+			s.logger.Error("Housekeeping pass failed", zap.String("task", task), zap.Error(err))
+		}
+	}
+}
+
+func (s *Scheduler) taskEnabled(task string) bool {
+	switch task {
+	case TaskDedupeMedia:
+		return s.cfg.DedupeMedia
+	case TaskPruneOrphanFiles:
+		return s.cfg.PruneOrphanFiles
+	case TaskPruneMissingFiles:
+		return s.cfg.PruneMissingFiles
+	case TaskCompactTaxonomy:
+		return s.cfg.CompactTaxonomy
+	default:
+		return false
+	}
+}
+
+// RunTask runs a single named pass to completion, regardless of whether
+// it is enabled for the periodic schedule. Used by both RunAll and the
+// on-demand admin endpoint.
+func (s *Scheduler) RunTask(ctx context.Context, task string) error {
+	var (
+		summary passSummary
+		err     error
+	)
+
+	start := time.Now()
+
+	switch task {
+	case TaskDedupeMedia:
+		summary, err = s.runInBatches(ctx, s.dedupeMediaBatch)
+	case TaskPruneOrphanFiles:
+		summary, err = s.runInBatches(ctx, s.pruneOrphanFilesBatch)
+	case TaskPruneMissingFiles:
+		summary, err = s.runInBatches(ctx, s.pruneMissingFilesBatch)
+	case TaskCompactTaxonomy:
+		summary, err = s.runInBatches(ctx, s.compactTaxonomyBatch)
+	default:
+		return fmt.Errorf("unknown housekeeping task %q", task)
+	}
+
+	logger := s.logger.With(
+		zap.String("task", task),
+		zap.Int("batches", summary.BatchesRun),
+		zap.Int("affected", summary.Affected),
+		zap.Duration("duration", time.Since(start)),
+	)
+
+	if err != nil {
+		logger.Error("Housekeeping pass failed", zap.Error(err))
+		return err
+	}
+
+	logger.Info("Housekeeping pass complete")
+	return nil
+}
+
+// runInBatches repeatedly invokes batchFn with the configured BatchSize
+// until a call reports fewer than batchSize affected (meaning the pass
+// is caught up). Each batchFn makes its database writes inside a
+// transaction: one per call, except dedupeMediaBatch, which uses one
+// per content-hash group.
+func (s *Scheduler) runInBatches(ctx context.Context, batchFn func(ctx context.Context, batchSize int) (int, error)) (passSummary, error) {
+	batchSize := s.cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	var summary passSummary
+	for {
+		affected, err := batchFn(ctx, batchSize)
+		if err != nil {
+			return summary, err
+		}
+
+		summary.BatchesRun++
+		summary.Affected += affected
+
+		if affected < batchSize {
+			return summary, nil
+		}
+	}
+}
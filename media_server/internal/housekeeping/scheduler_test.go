@@ -0,0 +1,64 @@
+package housekeeping
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/flex/media_server/internal/config"
+)
+
+func TestRunInBatches_StopsBelowAFullBatch(t *testing.T) {
+	s := &Scheduler{cfg: config.HousekeepingConfig{BatchSize: 10}}
+
+	calls := 0
+	results := []int{10, 10, 4}
+	batchFn := func(ctx context.Context, batchSize int) (int, error) {
+		got := results[calls]
+		calls++
+		return got, nil
+	}
+
+	summary, err := s.runInBatches(context.Background(), batchFn)
+	if err != nil {
+		t.Fatalf("runInBatches: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("batchFn called %d times, want 3", calls)
+	}
+	if summary.BatchesRun != 3 {
+		t.Errorf("BatchesRun = %d, want 3", summary.BatchesRun)
+	}
+	if summary.Affected != 24 {
+		t.Errorf("Affected = %d, want 24", summary.Affected)
+	}
+}
+
+func TestRunInBatches_PropagatesError(t *testing.T) {
+	s := &Scheduler{cfg: config.HousekeepingConfig{BatchSize: 10}}
+	wantErr := errors.New("boom")
+
+	_, err := s.runInBatches(context.Background(), func(ctx context.Context, batchSize int) (int, error) {
+		return 0, wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("runInBatches error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestTaskEnabled(t *testing.T) {
+	s := &Scheduler{cfg: config.HousekeepingConfig{
+		DedupeMedia:      true,
+		PruneOrphanFiles: false,
+	}}
+
+	if !s.taskEnabled(TaskDedupeMedia) {
+		t.Error("taskEnabled(TaskDedupeMedia) = false, want true")
+	}
+	if s.taskEnabled(TaskPruneOrphanFiles) {
+		t.Error("taskEnabled(TaskPruneOrphanFiles) = true, want false")
+	}
+	if s.taskEnabled("not_a_real_task") {
+		t.Error("taskEnabled of an unknown task = true, want false")
+	}
+}
@@ -0,0 +1,120 @@
+// Package indexer bridges the media watcher's Redis stream of
+// index/reindex/delete events into worker.Jobs. Without it, watcher
+// events are published but never consumed: this is what actually drains
+// flex:media:events into the worker pool.
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/flex/media_server/internal/media/watcher"
+	"github.com/yourusername/flex/media_server/internal/worker"
+)
+
+// consumerName identifies this process to the media-indexer consumer
+// group. A single replica is assumed for now, same as the worker pool.
+const consumerName = "indexer"
+
+// Bridge reads watcher.Event messages off watcher.StreamKey and
+// translates each into a worker.Job, enqueued onto queue.
+type Bridge struct {
+	redis  *redis.Client
+	queue  worker.Queue
+	logger *zap.Logger
+}
+
+// NewBridge creates a Bridge. Start creates the consumer group if it
+// doesn't already exist, so the bridge works whether or not a Watcher
+// in this process created it first.
+func NewBridge(redisClient *redis.Client, queue worker.Queue, logger *zap.Logger) *Bridge {
+	return &Bridge{redis: redisClient, queue: queue, logger: logger}
+}
+
+// Start ensures the consumer group exists, then drains the stream in
+// its own goroutine until ctx is canceled.
+func (b *Bridge) Start(ctx context.Context) error {
+	if err := b.redis.XGroupCreateMkStream(ctx, watcher.StreamKey, watcher.GroupName, "0").Err(); err != nil && !isBusyGroupErr(err) {
+		return fmt.Errorf("creating media events consumer group: %w", err)
+	}
+
+	go b.loop(ctx)
+	return nil
+}
+
+func (b *Bridge) loop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		streams, err := b.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    watcher.GroupName,
+			Consumer: consumerName,
+			Streams:  []string{watcher.StreamKey, ">"},
+			Count:    10,
+			Block:    5 * time.Second,
+		}).Result()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if err != redis.Nil {
+				b.logger.Warn("Failed to read media events", zap.Error(err))
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+
+		for _, msg := range streams[0].Messages {
+			b.handle(ctx, msg)
+		}
+	}
+}
+
+func (b *Bridge) handle(ctx context.Context, msg redis.XMessage) {
+	kind, _ := msg.Values["kind"].(string)
+	path, _ := msg.Values["path"].(string)
+
+	if job, ok := translateEvent(watcher.EventKind(kind), path); ok {
+		if err := b.queue.Enqueue(ctx, job); err != nil {
+			b.logger.Error("Failed to enqueue job for media event",
+				zap.String("kind", kind), zap.String("path", path), zap.Error(err))
+			// Left unacked in the group's pending list. loop reads only
+			// new entries (">"), so it does not redeliver this one.
+			return
+		}
+	} else {
+		b.logger.Warn("Unrecognized media event kind, dropping", zap.String("kind", kind), zap.String("path", path))
+	}
+
+	if err := b.redis.XAck(ctx, watcher.StreamKey, watcher.GroupName, msg.ID).Err(); err != nil {
+		b.logger.Error("Failed to ack media event", zap.String("id", msg.ID), zap.Error(err))
+	}
+}
+
+// translateEvent maps a watcher Event to the Job it should enqueue.
+// There is no media repository yet to assign a stable media ID before
+// the file is probed, so jobs are keyed on the path itself; once that
+// package exists, the probe/remove handlers are the natural place to
+// resolve the real media ID, and this can switch to keying on it.
+func translateEvent(kind watcher.EventKind, path string) (worker.Job, bool) {
+	switch kind {
+	case watcher.EventIndex, watcher.EventReindex:
+		return worker.NewProbeMediaJob(path, path), true
+	case watcher.EventDelete:
+		return worker.NewRemoveMediaJob(path, path), true
+	default:
+		return worker.Job{}, false
+	}
+}
+
+func isBusyGroupErr(err error) bool {
+	return err != nil && err.Error() == "BUSYGROUP Consumer Group name already exists"
+}
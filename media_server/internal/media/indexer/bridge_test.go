@@ -0,0 +1,32 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/yourusername/flex/media_server/internal/media/watcher"
+	"github.com/yourusername/flex/media_server/internal/worker"
+)
+
+func TestTranslateEvent(t *testing.T) {
+	cases := []struct {
+		kind    watcher.EventKind
+		wantOK  bool
+		wantJob worker.JobType
+	}{
+		{watcher.EventIndex, true, worker.JobProbeMedia},
+		{watcher.EventReindex, true, worker.JobProbeMedia},
+		{watcher.EventDelete, true, worker.JobRemoveMedia},
+		{watcher.EventKind("unknown"), false, ""},
+	}
+
+	for _, tc := range cases {
+		job, ok := translateEvent(tc.kind, "/media/movie.mkv")
+		if ok != tc.wantOK {
+			t.Errorf("translateEvent(%q) ok = %v, want %v", tc.kind, ok, tc.wantOK)
+			continue
+		}
+		if ok && job.Type != tc.wantJob {
+			t.Errorf("translateEvent(%q) job type = %q, want %q", tc.kind, job.Type, tc.wantJob)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package watcher
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// reconcileLoop periodically heals state that slipped past fsnotify,
+// e.g. events dropped during a restart or a slow network mount that
+// missed a rename notification.
+func (w *Watcher) reconcileLoop(ctx context.Context) {
+	if w.state == nil {
+		return
+	}
+
+	ticker := time.NewTicker(w.reconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Reconcile(ctx); err != nil {
+				w.logger.Error("Reconcile pass failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Reconcile lists the database's known file set, diffs it against what
+// is actually present on disk, and publishes index events for files
+// missing from the database and delete events for rows whose file is
+// gone.
+func (w *Watcher) Reconcile(ctx context.Context) error {
+	known, err := w.state.ListKnownPaths(ctx)
+	if err != nil {
+		return err
+	}
+
+	onDisk := make(map[string]struct{})
+	err = filepath.WalkDir(w.rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			onDisk[path] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	knownSet := make(map[string]struct{}, len(known))
+	var missing, orphaned int
+
+	for _, path := range known {
+		knownSet[path] = struct{}{}
+		if _, ok := onDisk[path]; !ok {
+			orphaned++
+			if err := w.publish(ctx, Event{Kind: EventDelete, Path: path}); err != nil {
+				w.logger.Error("Failed to publish orphan delete event", zap.String("path", path), zap.Error(err))
+			}
+		}
+	}
+
+	for path := range onDisk {
+		if _, ok := knownSet[path]; !ok {
+			missing++
+			if err := w.publish(ctx, Event{Kind: EventIndex, Path: path}); err != nil {
+				w.logger.Error("Failed to publish missing index event", zap.String("path", path), zap.Error(err))
+			}
+		}
+	}
+
+	w.logger.Info("Reconcile pass complete",
+		zap.Int("known", len(known)),
+		zap.Int("on_disk", len(onDisk)),
+		zap.Int("missing", missing),
+		zap.Int("orphaned", orphaned),
+	)
+
+	return nil
+}
@@ -0,0 +1,205 @@
+// Package watcher tails the media library on disk and turns filesystem
+// changes into index/reindex/delete jobs for the background workers,
+// so that freshly added or removed files show up without a manual scan.
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/flex/media_server/internal/config"
+)
+
+// StreamKey is the Redis stream that media events are published to.
+// Consumers read it as a consumer group so multiple worker replicas can
+// share the load without double-processing an event.
+const StreamKey = "flex:media:events"
+
+// GroupName is the Redis consumer group used by the indexing workers.
+const GroupName = "media-indexer"
+
+// EventKind identifies what happened to a path on disk.
+type EventKind string
+
+const (
+	EventIndex   EventKind = "index"   // new file, needs probing and indexing
+	EventReindex EventKind = "reindex" // existing file changed, re-probe
+	EventDelete  EventKind = "delete"  // file is gone, remove from the index
+)
+
+// Event describes a single coalesced filesystem change.
+type Event struct {
+	Kind EventKind
+	Path string
+}
+
+// StateLister reports the set of file paths the database currently
+// believes make up the media library, so Reconcile can diff it against
+// what is actually on disk. No type in this module implements it;
+// cmd/main.go passes nil, which leaves the reconcile pass off.
+type StateLister interface {
+	ListKnownPaths(ctx context.Context) ([]string, error)
+}
+
+// Watcher walks MediaConfig.RootPath on startup, then watches it for
+// changes and enqueues debounced index/reindex/delete events onto a
+// Redis stream for out-of-band processing.
+type Watcher struct {
+	rootPath          string
+	debounce          time.Duration
+	reconcileInterval time.Duration
+
+	fsw    *fsnotify.Watcher
+	redis  *redis.Client
+	logger *zap.Logger
+	state  StateLister
+
+	mu      sync.Mutex
+	pending map[string]*time.Timer
+}
+
+// New creates a Watcher for cfg.RootPath. redisClient is used to publish
+// events; state, if non-nil, enables the reconcile pass.
+func New(cfg config.MediaConfig, redisClient *redis.Client, state StateLister, logger *zap.Logger) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating fsnotify watcher: %w", err)
+	}
+
+	debounce := cfg.WatchDebounce
+	if debounce <= 0 {
+		debounce = 5 * time.Second
+	}
+	reconcileInterval := cfg.ReconcileInterval
+	if reconcileInterval <= 0 {
+		reconcileInterval = time.Hour
+	}
+
+	return &Watcher{
+		rootPath:          cfg.RootPath,
+		debounce:          debounce,
+		reconcileInterval: reconcileInterval,
+		fsw:               fsw,
+		redis:             redisClient,
+		logger:            logger,
+		state:             state,
+		pending:           make(map[string]*time.Timer),
+	}, nil
+}
+
+// Start seeds watches for the existing directory tree, ensures the Redis
+// consumer group exists, then watches for changes until ctx is canceled.
+func (w *Watcher) Start(ctx context.Context) error {
+	if err := w.redis.XGroupCreateMkStream(ctx, StreamKey, GroupName, "0").Err(); err != nil && !isBusyGroupErr(err) {
+		return fmt.Errorf("creating consumer group: %w", err)
+	}
+
+	if err := w.seed(); err != nil {
+		return fmt.Errorf("seeding watches: %w", err)
+	}
+
+	go w.loop(ctx)
+	go w.reconcileLoop(ctx)
+
+	return nil
+}
+
+// Close releases the underlying fsnotify watcher.
+func (w *Watcher) Close() error {
+	return w.fsw.Close()
+}
+
+// seed walks the tree once to add a watch on every existing directory.
+func (w *Watcher) seed() error {
+	return filepath.WalkDir(w.rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return w.fsw.Add(path)
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) loop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.handle(event)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			w.logger.Warn("Filesystem watch error", zap.Error(err))
+		}
+	}
+}
+
+func (w *Watcher) handle(event fsnotify.Event) {
+	switch {
+	case event.Op&fsnotify.Create != 0:
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if err := w.fsw.Add(event.Name); err != nil {
+				w.logger.Warn("Failed to watch new directory", zap.String("path", event.Name), zap.Error(err))
+			}
+			return
+		}
+		w.schedule(EventIndex, event.Name)
+	case event.Op&fsnotify.Write != 0:
+		w.schedule(EventReindex, event.Name)
+	case event.Op&(fsnotify.Rename|fsnotify.Remove) != 0:
+		w.schedule(EventDelete, event.Name)
+	}
+}
+
+// schedule debounces bursts of events for the same path (e.g. the many
+// writes a large file copy produces) into a single job, fired after the
+// watcher's quiet window has elapsed.
+func (w *Watcher) schedule(kind EventKind, path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if timer, ok := w.pending[path]; ok {
+		timer.Stop()
+	}
+
+	w.pending[path] = time.AfterFunc(w.debounce, func() {
+		w.mu.Lock()
+		delete(w.pending, path)
+		w.mu.Unlock()
+
+		if err := w.publish(context.Background(), Event{Kind: kind, Path: path}); err != nil {
+			w.logger.Error("Failed to publish media event",
+				zap.String("path", path), zap.String("kind", string(kind)), zap.Error(err))
+		}
+	})
+}
+
+func (w *Watcher) publish(ctx context.Context, event Event) error {
+	return w.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: StreamKey,
+		Values: map[string]interface{}{
+			"kind": string(event.Kind),
+			"path": event.Path,
+		},
+	}).Err()
+}
+
+func isBusyGroupErr(err error) bool {
+	return err != nil && err.Error() == "BUSYGROUP Consumer Group name already exists"
+}
@@ -0,0 +1,74 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// newTestWatcher builds a Watcher with a short debounce and a Redis
+// client pointed at a closed port. publish() will fail against it, but
+// schedule()'s debounce/coalescing behavior doesn't depend on publish
+// succeeding.
+func newTestWatcher(t *testing.T, debounce time.Duration) *Watcher {
+	t.Helper()
+	return &Watcher{
+		rootPath: t.TempDir(),
+		debounce: debounce,
+		redis:    redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"}),
+		logger:   zap.NewNop(),
+		pending:  make(map[string]*time.Timer),
+	}
+}
+
+func TestWatcher_ScheduleCoalescesBurstsPerPath(t *testing.T) {
+	w := newTestWatcher(t, 50*time.Millisecond)
+
+	w.schedule(EventReindex, "/media/movie.mkv")
+	w.schedule(EventReindex, "/media/movie.mkv")
+	w.schedule(EventReindex, "/media/movie.mkv")
+
+	w.mu.Lock()
+	pending := len(w.pending)
+	w.mu.Unlock()
+
+	if pending != 1 {
+		t.Fatalf("pending timers for one path = %d, want 1", pending)
+	}
+}
+
+func TestWatcher_ScheduleFiresAfterDebounceWindow(t *testing.T) {
+	w := newTestWatcher(t, 20*time.Millisecond)
+
+	w.schedule(EventIndex, "/media/new.mkv")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		w.mu.Lock()
+		pending := len(w.pending)
+		w.mu.Unlock()
+		if pending == 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatal("pending timer was not cleared after the debounce window elapsed")
+}
+
+func TestWatcher_ScheduleTracksDistinctPathsSeparately(t *testing.T) {
+	w := newTestWatcher(t, 50*time.Millisecond)
+
+	w.schedule(EventIndex, "/media/a.mkv")
+	w.schedule(EventIndex, "/media/b.mkv")
+
+	w.mu.Lock()
+	pending := len(w.pending)
+	w.mu.Unlock()
+
+	if pending != 2 {
+		t.Fatalf("pending timers for two distinct paths = %d, want 2", pending)
+	}
+}
@@ -0,0 +1,33 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// getDetailsCache returns a cached Details for key, if present.
+func getDetailsCache(ctx context.Context, client *redis.Client, key string) (*Details, bool) {
+	raw, err := client.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	var details Details
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return nil, false
+	}
+
+	return &details, true
+}
+
+// setDetailsCache caches details under key for ttl.
+func setDetailsCache(ctx context.Context, client *redis.Client, key string, details *Details, ttl time.Duration) {
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return
+	}
+	_ = client.Set(ctx, key, raw, ttl).Err()
+}
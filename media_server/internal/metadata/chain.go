@@ -0,0 +1,252 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sony/gobreaker"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/yourusername/flex/media_server/internal/config"
+)
+
+// ErrAllProvidersFailed is returned when every enabled provider failed
+// (or was skipped by its circuit breaker or rate limiter) for a given
+// lookup.
+var ErrAllProvidersFailed = errors.New("metadata: all providers failed")
+
+// entry pairs a Provider with its own circuit breaker and rate limiter,
+// so one provider's outage or quota never blocks another's.
+type entry struct {
+	provider Provider
+	breaker  *gobreaker.CircuitBreaker
+	limiter  *rate.Limiter
+}
+
+// Chain queries Provider implementations in priority order (the order
+// config.ExternalConfig.Providers lists them) and, for FetchDetails,
+// merges their results field-by-field: the first provider to supply a
+// given field wins.
+type Chain struct {
+	entries  []*entry
+	cache    *redis.Client
+	cacheTTL time.Duration
+	logger   *zap.Logger
+}
+
+// NewChain builds a Chain from cfg.Providers, wiring a gobreaker circuit
+// breaker and a token-bucket rate limiter around each enabled provider.
+// Unknown provider names are logged and skipped rather than failing
+// startup, so a typo in config.yaml degrades gracefully.
+func NewChain(cfg config.ExternalConfig, cache *redis.Client, logger *zap.Logger) *Chain {
+	c := &Chain{cache: cache, cacheTTL: cfg.CacheTTL, logger: logger}
+
+	for _, pc := range cfg.Providers {
+		if !pc.Enabled {
+			continue
+		}
+
+		provider, err := buildProvider(pc.Name, cfg)
+		if err != nil {
+			logger.Warn("Skipping unknown metadata provider", zap.String("provider", pc.Name), zap.Error(err))
+			continue
+		}
+
+		c.entries = append(c.entries, &entry{
+			provider: provider,
+			breaker:  newBreaker(pc),
+			limiter:  rate.NewLimiter(rate.Limit(pc.QPS), maxInt(1, int(pc.QPS))),
+		})
+	}
+
+	return c
+}
+
+func buildProvider(name string, cfg config.ExternalConfig) (Provider, error) {
+	switch name {
+	case "tmdb":
+		return NewTMDBProvider(cfg.TMDBAPIKey), nil
+	case "omdb":
+		return NewOMDBProvider(cfg.OMDBAPIKey), nil
+	case "local_filename":
+		return NewLocalFilenameProvider(), nil
+	default:
+		return nil, fmt.Errorf("no provider registered for name %q", name)
+	}
+}
+
+func newBreaker(pc config.ProviderConfig) *gobreaker.CircuitBreaker {
+	return gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name: pc.Name,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			if counts.Requests < 5 {
+				return false
+			}
+			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
+			return failureRatio >= pc.BreakerFailureRatio
+		},
+		Timeout: pc.BreakerCooldown,
+	})
+}
+
+// SearchMovie tries each enabled provider in priority order and returns
+// the first non-empty result set.
+func (c *Chain) SearchMovie(ctx context.Context, query string, year int) ([]SearchResult, error) {
+	return firstSuccessGeneric(ctx, c, "search_movie",
+		func(p Provider) ([]SearchResult, error) { return p.SearchMovie(ctx, query, year) },
+		func(r []SearchResult) bool { return len(r) == 0 })
+}
+
+// SearchTVShow tries each enabled provider in priority order and
+// returns the first non-empty result set.
+func (c *Chain) SearchTVShow(ctx context.Context, query string, year int) ([]SearchResult, error) {
+	return firstSuccessGeneric(ctx, c, "search_tv",
+		func(p Provider) ([]SearchResult, error) { return p.SearchTVShow(ctx, query, year) },
+		func(r []SearchResult) bool { return len(r) == 0 })
+}
+
+// FetchPoster tries each enabled provider in priority order and returns
+// the first poster image any of them can supply.
+func (c *Chain) FetchPoster(ctx context.Context, externalID string) ([]byte, error) {
+	return firstSuccessGeneric(ctx, c, "fetch_poster",
+		func(p Provider) ([]byte, error) { return p.FetchPoster(ctx, externalID) },
+		func(b []byte) bool { return len(b) == 0 })
+}
+
+// ResolveIDs searches every enabled provider for query/year and returns
+// a map of provider name to that provider's own external ID for its
+// first result. Each provider has its own ID namespace (TMDB's numeric
+// ID, OMDB's IMDb ID, the local provider's filename), so the returned
+// map is what FetchDetails needs to query each provider with an ID it
+// actually understands. Providers with no hit are simply absent from
+// the map rather than failing the whole lookup.
+func (c *Chain) ResolveIDs(ctx context.Context, query string, year int) (map[string]string, error) {
+	ids := make(map[string]string)
+
+	for _, e := range c.entries {
+		results, err := call(ctx, e, "search_movie", func() ([]SearchResult, error) {
+			return e.provider.SearchMovie(ctx, query, year)
+		})
+		if err != nil || len(results) == 0 {
+			continue
+		}
+		ids[e.provider.Name()] = results[0].ExternalID
+	}
+
+	if len(ids) == 0 {
+		return nil, ErrAllProvidersFailed
+	}
+	return ids, nil
+}
+
+// FetchDetails queries every enabled provider with its own ID from ids
+// (keyed by provider name, as returned by ResolveIDs) and merges the
+// results field-by-field, caching the merged record in Redis for
+// cfg.CacheTTL. Providers absent from ids are skipped.
+func (c *Chain) FetchDetails(ctx context.Context, ids map[string]string) (*Details, error) {
+	cacheKey := detailsCacheKey(ids)
+	if cached, ok := getDetailsCache(ctx, c.cache, cacheKey); ok {
+		cacheHitsTotal.WithLabelValues("fetch_details").Inc()
+		return cached, nil
+	}
+
+	merged := &Details{}
+	var anySuccess bool
+
+	for _, e := range c.entries {
+		externalID, ok := ids[e.provider.Name()]
+		if !ok {
+			continue
+		}
+
+		details, err := call(ctx, e, "fetch_details", func() (*Details, error) {
+			return e.provider.FetchDetails(ctx, externalID)
+		})
+		if err != nil {
+			c.logger.Warn("Metadata provider failed, trying next",
+				zap.String("provider", e.provider.Name()), zap.Error(err))
+			continue
+		}
+		anySuccess = true
+		mergeDetails(merged, details)
+	}
+
+	if !anySuccess {
+		return nil, ErrAllProvidersFailed
+	}
+
+	setDetailsCache(ctx, c.cache, cacheKey, merged, c.cacheTTL)
+	return merged, nil
+}
+
+// detailsCacheKey derives a stable Redis key from a provider-name-to-ID
+// map, since FetchDetails no longer has a single externalID to key on.
+func detailsCacheKey(ids map[string]string) string {
+	pairs := make([]string, 0, len(ids))
+	for provider, id := range ids {
+		pairs = append(pairs, provider+"="+id)
+	}
+	sort.Strings(pairs)
+	return "flex:metadata:details:" + strings.Join(pairs, "&")
+}
+
+// firstSuccessGeneric calls fn against each entry in priority order,
+// skipping a result for which empty reports true (a valid "no hit"
+// rather than an error) and returning the first result that is neither
+// an error nor empty, or ErrAllProvidersFailed.
+func firstSuccessGeneric[T any](ctx context.Context, c *Chain, method string, fn func(Provider) (T, error), empty func(T) bool) (T, error) {
+	var zero T
+	for _, e := range c.entries {
+		result, err := call(ctx, e, method, func() (T, error) { return fn(e.provider) })
+		if err != nil {
+			c.logger.Warn("Metadata provider failed, trying next",
+				zap.String("provider", e.provider.Name()), zap.String("method", method), zap.Error(err))
+			continue
+		}
+		if empty(result) {
+			continue
+		}
+		return result, nil
+	}
+	return zero, ErrAllProvidersFailed
+}
+
+// call enforces the rate limiter and circuit breaker around a single
+// provider invocation, recording Prometheus counters either way.
+func call[T any](ctx context.Context, e *entry, method string, fn func() (T, error)) (T, error) {
+	var zero T
+	provider := e.provider.Name()
+	providerRequestsTotal.WithLabelValues(provider, method).Inc()
+
+	if err := e.limiter.Wait(ctx); err != nil {
+		providerFailuresTotal.WithLabelValues(provider, method).Inc()
+		return zero, fmt.Errorf("%s: rate limiter: %w", provider, err)
+	}
+
+	result, err := e.breaker.Execute(func() (interface{}, error) {
+		return fn()
+	})
+	if err != nil {
+		if errors.Is(err, gobreaker.ErrOpenState) {
+			providerCircuitOpenTotal.WithLabelValues(provider, method).Inc()
+		} else {
+			providerFailuresTotal.WithLabelValues(provider, method).Inc()
+		}
+		return zero, fmt.Errorf("%s: %w", provider, err)
+	}
+
+	return result.(T), nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,138 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/yourusername/flex/media_server/internal/config"
+)
+
+// fakeProvider is a scriptable Provider for exercising Chain without
+// hitting a real TMDB/OMDB/filesystem backend.
+type fakeProvider struct {
+	name          string
+	searchResults []SearchResult
+	searchErr     error
+	details       map[string]*Details // keyed by externalID
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) SearchMovie(ctx context.Context, query string, year int) ([]SearchResult, error) {
+	return p.searchResults, p.searchErr
+}
+
+func (p *fakeProvider) SearchTVShow(ctx context.Context, query string, year int) ([]SearchResult, error) {
+	return p.searchResults, p.searchErr
+}
+
+func (p *fakeProvider) FetchDetails(ctx context.Context, externalID string) (*Details, error) {
+	if d, ok := p.details[externalID]; ok {
+		return d, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (p *fakeProvider) FetchPoster(ctx context.Context, externalID string) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+// testChain builds a Chain directly from providers, bypassing
+// NewChain/buildProvider so the test doesn't need real API keys.
+func testChain(providers ...Provider) *Chain {
+	c := &Chain{logger: zap.NewNop()}
+	for _, p := range providers {
+		pc := config.ProviderConfig{Name: p.Name(), BreakerFailureRatio: 0.5}
+		c.entries = append(c.entries, &entry{
+			provider: p,
+			breaker:  newBreaker(pc),
+			limiter:  rate.NewLimiter(rate.Inf, 1),
+		})
+	}
+	return c
+}
+
+func TestChain_SearchMovieSkipsEmptyResults(t *testing.T) {
+	empty := &fakeProvider{name: "tmdb"}
+	hit := &fakeProvider{name: "omdb", searchResults: []SearchResult{{Provider: "omdb", ExternalID: "tt123", Title: "A Movie"}}}
+
+	c := testChain(empty, hit)
+
+	results, err := c.SearchMovie(context.Background(), "a movie", 0)
+	if err != nil {
+		t.Fatalf("SearchMovie: %v", err)
+	}
+	if len(results) != 1 || results[0].ExternalID != "tt123" {
+		t.Fatalf("SearchMovie results = %+v, want a single tt123 hit from omdb", results)
+	}
+}
+
+func TestChain_SearchMovieAllEmptyReturnsErrAllProvidersFailed(t *testing.T) {
+	c := testChain(&fakeProvider{name: "tmdb"}, &fakeProvider{name: "omdb"})
+
+	_, err := c.SearchMovie(context.Background(), "nothing", 0)
+	if !errors.Is(err, ErrAllProvidersFailed) {
+		t.Fatalf("SearchMovie error = %v, want ErrAllProvidersFailed", err)
+	}
+}
+
+func TestChain_ResolveIDsCollectsPerProviderIDs(t *testing.T) {
+	tmdb := &fakeProvider{name: "tmdb", searchResults: []SearchResult{{ExternalID: "603"}}}
+	omdb := &fakeProvider{name: "omdb", searchResults: []SearchResult{{ExternalID: "tt0133093"}}}
+	noHit := &fakeProvider{name: "local_filename"}
+
+	c := testChain(tmdb, omdb, noHit)
+
+	ids, err := c.ResolveIDs(context.Background(), "The Matrix", 1999)
+	if err != nil {
+		t.Fatalf("ResolveIDs: %v", err)
+	}
+
+	want := map[string]string{"tmdb": "603", "omdb": "tt0133093"}
+	if len(ids) != len(want) {
+		t.Fatalf("ResolveIDs = %v, want %v", ids, want)
+	}
+	for provider, id := range want {
+		if ids[provider] != id {
+			t.Errorf("ResolveIDs[%q] = %q, want %q", provider, ids[provider], id)
+		}
+	}
+	if _, ok := ids["local_filename"]; ok {
+		t.Errorf("ResolveIDs included %q, which had no hit", "local_filename")
+	}
+}
+
+func TestMergeDetails_FirstNonEmptyWins(t *testing.T) {
+	dst := &Details{Title: "The Matrix"}
+	src := &Details{
+		Title:       "should not override",
+		Overview:    "A hacker discovers reality is a simulation.",
+		ReleaseDate: "1999-03-31",
+		Genres:      []string{"Action", "Sci-Fi"},
+	}
+
+	mergeDetails(dst, src)
+
+	if dst.Title != "The Matrix" {
+		t.Errorf("Title = %q, want the already-set value preserved", dst.Title)
+	}
+	if dst.Overview != src.Overview {
+		t.Errorf("Overview = %q, want %q filled in from src", dst.Overview, src.Overview)
+	}
+	if len(dst.Genres) != 2 {
+		t.Errorf("Genres = %v, want filled in from src", dst.Genres)
+	}
+}
+
+func TestDetailsCacheKey_StableAcrossMapOrdering(t *testing.T) {
+	a := detailsCacheKey(map[string]string{"tmdb": "603", "omdb": "tt0133093"})
+	b := detailsCacheKey(map[string]string{"omdb": "tt0133093", "tmdb": "603"})
+
+	if a != b {
+		t.Errorf("detailsCacheKey is order-dependent: %q != %q", a, b)
+	}
+}
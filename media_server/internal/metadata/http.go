@@ -0,0 +1,30 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// downloadBytes fetches imageURL and returns its body, shared by
+// providers whose FetchPoster just downloads a URL FetchDetails
+// already resolved.
+func downloadBytes(ctx context.Context, client *http.Client, imageURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", imageURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: status %d", imageURL, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
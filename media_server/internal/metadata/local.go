@@ -0,0 +1,77 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yearInBrackets matches a 4-digit year in parentheses or square
+// brackets anywhere in the filename, e.g. "Movie Title (2019).mkv".
+var yearInBrackets = regexp.MustCompile(`[\(\[](\d{4})[\)\]]`)
+
+// LocalFilenameProvider derives a best-effort title and year from the
+// filename itself. Its searches and FetchDetails never fail and it never
+// reaches the network; FetchPoster always returns an error. It is listed
+// last in the default provider order, so there it only fills gaps TMDB
+// and OMDB left empty.
+type LocalFilenameProvider struct{}
+
+// NewLocalFilenameProvider creates a LocalFilenameProvider.
+func NewLocalFilenameProvider() *LocalFilenameProvider {
+	return &LocalFilenameProvider{}
+}
+
+func (p *LocalFilenameProvider) Name() string { return "local_filename" }
+
+func (p *LocalFilenameProvider) SearchMovie(ctx context.Context, query string, year int) ([]SearchResult, error) {
+	return p.search(query, year)
+}
+
+func (p *LocalFilenameProvider) SearchTVShow(ctx context.Context, query string, year int) ([]SearchResult, error) {
+	return p.search(query, year)
+}
+
+func (p *LocalFilenameProvider) search(query string, year int) ([]SearchResult, error) {
+	title, parsedYear := ParseFilename(query)
+	if year > 0 {
+		parsedYear = year
+	}
+	return []SearchResult{{
+		Provider:   p.Name(),
+		ExternalID: query,
+		Title:      title,
+		Year:       parsedYear,
+	}}, nil
+}
+
+func (p *LocalFilenameProvider) FetchDetails(ctx context.Context, externalID string) (*Details, error) {
+	title, year := ParseFilename(externalID)
+	var releaseDate string
+	if year > 0 {
+		releaseDate = strconv.Itoa(year)
+	}
+	return &Details{Title: title, ReleaseDate: releaseDate}, nil
+}
+
+func (p *LocalFilenameProvider) FetchPoster(ctx context.Context, externalID string) ([]byte, error) {
+	return nil, fmt.Errorf("local_filename: no poster available for %s", externalID)
+}
+
+// ParseFilename extracts a human-readable title and release year from
+// a media filename, stripping the extension and swapping common
+// separators ("."  and "_") for spaces.
+func ParseFilename(path string) (title string, year int) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	if match := yearInBrackets.FindStringSubmatchIndex(name); match != nil {
+		year, _ = strconv.Atoi(name[match[2]:match[3]])
+		name = name[:match[0]]
+	}
+
+	name = strings.NewReplacer(".", " ", "_", " ").Replace(name)
+	return strings.TrimSpace(name), year
+}
@@ -0,0 +1,29 @@
+package metadata
+
+// mergeDetails fills zero-value fields of dst with the corresponding
+// field from src, implementing the Chain's "first non-empty wins"
+// field-by-field merge. Fields already set in dst (by a
+// higher-priority provider) are left untouched.
+func mergeDetails(dst *Details, src *Details) {
+	if dst.Title == "" {
+		dst.Title = src.Title
+	}
+	if dst.Overview == "" {
+		dst.Overview = src.Overview
+	}
+	if dst.ReleaseDate == "" {
+		dst.ReleaseDate = src.ReleaseDate
+	}
+	if len(dst.Genres) == 0 {
+		dst.Genres = src.Genres
+	}
+	if len(dst.Cast) == 0 {
+		dst.Cast = src.Cast
+	}
+	if dst.PosterURL == "" {
+		dst.PosterURL = src.PosterURL
+	}
+	if dst.Runtime == 0 {
+		dst.Runtime = src.Runtime
+	}
+}
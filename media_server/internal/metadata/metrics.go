@@ -0,0 +1,28 @@
+package metadata
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	providerRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "flex_metadata_provider_requests_total",
+		Help: "Metadata provider calls attempted, by provider and method.",
+	}, []string{"provider", "method"})
+
+	providerFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "flex_metadata_provider_failures_total",
+		Help: "Metadata provider calls that returned an error, by provider and method.",
+	}, []string{"provider", "method"})
+
+	providerCircuitOpenTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "flex_metadata_provider_circuit_open_total",
+		Help: "Calls rejected because a provider's circuit breaker was open, by provider and method.",
+	}, []string{"provider", "method"})
+
+	cacheHitsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "flex_metadata_cache_hits_total",
+		Help: "Metadata lookups served from the Redis cache, by method.",
+	}, []string{"method"})
+)
@@ -0,0 +1,151 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const omdbBaseURL = "https://www.omdbapi.com/"
+
+// OMDBProvider queries the Open Movie Database. OMDB has no separate
+// search-vs-details concept for a known title, so SearchMovie/SearchTVShow
+// and FetchDetails share the same lookup and FetchPoster parses the
+// poster URL out of it.
+type OMDBProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+// NewOMDBProvider creates an OMDBProvider. apiKey is ExternalConfig.OMDBAPIKey.
+func NewOMDBProvider(apiKey string) *OMDBProvider {
+	return &OMDBProvider{apiKey: apiKey, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (p *OMDBProvider) Name() string { return "omdb" }
+
+type omdbResponse struct {
+	ImdbID   string `json:"imdbID"`
+	Title    string `json:"Title"`
+	Year     string `json:"Year"`
+	Released string `json:"Released"`
+	Runtime  string `json:"Runtime"`
+	Genre    string `json:"Genre"`
+	Actors   string `json:"Actors"`
+	Plot     string `json:"Plot"`
+	Poster   string `json:"Poster"`
+	Response string `json:"Response"`
+	Error    string `json:"Error"`
+}
+
+func (p *OMDBProvider) SearchMovie(ctx context.Context, query string, year int) ([]SearchResult, error) {
+	return p.search(ctx, query, year, "movie")
+}
+
+func (p *OMDBProvider) SearchTVShow(ctx context.Context, query string, year int) ([]SearchResult, error) {
+	return p.search(ctx, query, year, "series")
+}
+
+func (p *OMDBProvider) search(ctx context.Context, query string, year int, mediaType string) ([]SearchResult, error) {
+	body, err := p.lookupByTitle(ctx, query, year, mediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	return []SearchResult{{
+		Provider:   p.Name(),
+		ExternalID: body.ImdbID,
+		Title:      body.Title,
+		Year:       yearFromDate(strings.SplitN(body.Year, "–", 2)[0]),
+	}}, nil
+}
+
+func (p *OMDBProvider) FetchDetails(ctx context.Context, externalID string) (*Details, error) {
+	params := url.Values{"apikey": {p.apiKey}, "i": {externalID}, "plot": {"full"}}
+	body, err := p.get(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var genres []string
+	if body.Genre != "" {
+		for _, g := range strings.Split(body.Genre, ",") {
+			genres = append(genres, strings.TrimSpace(g))
+		}
+	}
+
+	var cast []string
+	if body.Actors != "" {
+		for _, a := range strings.Split(body.Actors, ",") {
+			cast = append(cast, strings.TrimSpace(a))
+		}
+	}
+
+	var posterURL string
+	if body.Poster != "" && body.Poster != "N/A" {
+		posterURL = body.Poster
+	}
+
+	return &Details{
+		Title:       body.Title,
+		Overview:    body.Plot,
+		ReleaseDate: body.Released,
+		Genres:      genres,
+		Cast:        cast,
+		PosterURL:   posterURL,
+		Runtime:     parseRuntimeMinutes(body.Runtime),
+	}, nil
+}
+
+func (p *OMDBProvider) FetchPoster(ctx context.Context, externalID string) ([]byte, error) {
+	details, err := p.FetchDetails(ctx, externalID)
+	if err != nil {
+		return nil, err
+	}
+	if details.PosterURL == "" {
+		return nil, fmt.Errorf("omdb: no poster available for %s", externalID)
+	}
+	return downloadBytes(ctx, p.client, details.PosterURL)
+}
+
+func (p *OMDBProvider) lookupByTitle(ctx context.Context, title string, year int, mediaType string) (*omdbResponse, error) {
+	params := url.Values{"apikey": {p.apiKey}, "t": {title}, "type": {mediaType}}
+	if year > 0 {
+		params.Set("y", strconv.Itoa(year))
+	}
+	return p.get(ctx, params)
+}
+
+func (p *OMDBProvider) get(ctx context.Context, params url.Values) (*omdbResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, omdbBaseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("omdb request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body omdbResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding omdb response: %w", err)
+	}
+	if body.Response == "False" {
+		return nil, fmt.Errorf("omdb: %s", body.Error)
+	}
+
+	return &body, nil
+}
+
+// parseRuntimeMinutes parses OMDB's "123 min" runtime format.
+func parseRuntimeMinutes(runtime string) int {
+	minutes, _ := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(runtime, "min")))
+	return minutes
+}
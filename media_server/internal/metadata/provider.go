@@ -0,0 +1,40 @@
+// Package metadata abstracts movie/TV metadata lookups behind a
+// Provider interface, so TMDB, OMDB, and a local-filename fallback can
+// be queried through a single Chain that tolerates any one of them
+// being down, rate-limited, or simply disabled.
+package metadata
+
+import "context"
+
+// SearchResult is a single candidate match from a provider's search.
+type SearchResult struct {
+	Provider   string `json:"provider"`
+	ExternalID string `json:"external_id"`
+	Title      string `json:"title"`
+	Year       int    `json:"year"`
+}
+
+// Details is the merged metadata record for a single title. Fields a
+// provider could not supply are left at their zero value so Chain can
+// fill them in from the next provider.
+type Details struct {
+	Title       string   `json:"title"`
+	Overview    string   `json:"overview"`
+	ReleaseDate string   `json:"release_date"`
+	Genres      []string `json:"genres"`
+	Cast        []string `json:"cast"`
+	PosterURL   string   `json:"poster_url"`
+	Runtime     int      `json:"runtime_minutes"`
+}
+
+// Provider is implemented by each metadata backend (TMDB, OMDB, a
+// local-filename fallback, ...).
+type Provider interface {
+	// Name identifies the provider in config, logs, and metrics.
+	Name() string
+
+	SearchMovie(ctx context.Context, query string, year int) ([]SearchResult, error)
+	SearchTVShow(ctx context.Context, query string, year int) ([]SearchResult, error)
+	FetchDetails(ctx context.Context, externalID string) (*Details, error)
+	FetchPoster(ctx context.Context, externalID string) ([]byte, error)
+}
@@ -0,0 +1,165 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const tmdbBaseURL = "https://api.themoviedb.org/3"
+
+// TMDBProvider queries The Movie Database.
+type TMDBProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+// NewTMDBProvider creates a TMDBProvider. apiKey is ExternalConfig.TMDBAPIKey.
+func NewTMDBProvider(apiKey string) *TMDBProvider {
+	return &TMDBProvider{apiKey: apiKey, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (p *TMDBProvider) Name() string { return "tmdb" }
+
+func (p *TMDBProvider) SearchMovie(ctx context.Context, query string, year int) ([]SearchResult, error) {
+	return p.search(ctx, "movie", query, year)
+}
+
+func (p *TMDBProvider) SearchTVShow(ctx context.Context, query string, year int) ([]SearchResult, error) {
+	return p.search(ctx, "tv", query, year)
+}
+
+func (p *TMDBProvider) search(ctx context.Context, mediaType, query string, year int) ([]SearchResult, error) {
+	params := url.Values{"api_key": {p.apiKey}, "query": {query}}
+	if year > 0 {
+		yearParam := "year"
+		if mediaType == "tv" {
+			yearParam = "first_air_date_year"
+		}
+		params.Set(yearParam, strconv.Itoa(year))
+	}
+
+	var body struct {
+		Results []struct {
+			ID           int    `json:"id"`
+			Title        string `json:"title"`
+			Name         string `json:"name"`
+			ReleaseDate  string `json:"release_date"`
+			FirstAirDate string `json:"first_air_date"`
+		} `json:"results"`
+	}
+	if err := p.get(ctx, fmt.Sprintf("/search/%s", mediaType), params, &body); err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(body.Results))
+	for _, r := range body.Results {
+		title := r.Title
+		date := r.ReleaseDate
+		if mediaType == "tv" {
+			title = r.Name
+			date = r.FirstAirDate
+		}
+		results = append(results, SearchResult{
+			Provider:   p.Name(),
+			ExternalID: strconv.Itoa(r.ID),
+			Title:      title,
+			Year:       yearFromDate(date),
+		})
+	}
+	return results, nil
+}
+
+func (p *TMDBProvider) FetchDetails(ctx context.Context, externalID string) (*Details, error) {
+	var body struct {
+		Title       string `json:"title"`
+		Overview    string `json:"overview"`
+		ReleaseDate string `json:"release_date"`
+		Runtime     int    `json:"runtime"`
+		PosterPath  string `json:"poster_path"`
+		Genres      []struct {
+			Name string `json:"name"`
+		} `json:"genres"`
+		Credits struct {
+			Cast []struct {
+				Name string `json:"name"`
+			} `json:"cast"`
+		} `json:"credits"`
+	}
+
+	params := url.Values{"api_key": {p.apiKey}, "append_to_response": {"credits"}}
+	if err := p.get(ctx, "/movie/"+externalID, params, &body); err != nil {
+		return nil, err
+	}
+
+	genres := make([]string, 0, len(body.Genres))
+	for _, g := range body.Genres {
+		genres = append(genres, g.Name)
+	}
+
+	cast := make([]string, 0, len(body.Credits.Cast))
+	for _, c := range body.Credits.Cast {
+		cast = append(cast, c.Name)
+	}
+
+	var posterURL string
+	if body.PosterPath != "" {
+		posterURL = "https://image.tmdb.org/t/p/original" + body.PosterPath
+	}
+
+	return &Details{
+		Title:       body.Title,
+		Overview:    body.Overview,
+		ReleaseDate: body.ReleaseDate,
+		Genres:      genres,
+		Cast:        cast,
+		PosterURL:   posterURL,
+		Runtime:     body.Runtime,
+	}, nil
+}
+
+func (p *TMDBProvider) FetchPoster(ctx context.Context, externalID string) ([]byte, error) {
+	details, err := p.FetchDetails(ctx, externalID)
+	if err != nil {
+		return nil, err
+	}
+	if details.PosterURL == "" {
+		return nil, fmt.Errorf("tmdb: no poster available for %s", externalID)
+	}
+	return p.download(ctx, details.PosterURL)
+}
+
+func (p *TMDBProvider) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tmdbBaseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("tmdb request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("tmdb request returned status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (p *TMDBProvider) download(ctx context.Context, imageURL string) ([]byte, error) {
+	return downloadBytes(ctx, p.client, imageURL)
+}
+
+// yearFromDate extracts the year from a "YYYY-MM-DD" date string,
+// returning 0 if it can't be parsed.
+func yearFromDate(date string) int {
+	year, _ := strconv.Atoi(strings.SplitN(date, "-", 2)[0])
+	return year
+}
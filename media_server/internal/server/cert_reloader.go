@@ -0,0 +1,89 @@
+// Package server builds the TLS configuration (self-managed certs or
+// ACME/autocert) and the HTTP->HTTPS redirector that cmd/main.go serves
+// alongside the API.
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// statInterval is how often CertReloader re-stats the certificate file
+// to notice a renewal.
+const statInterval = time.Minute
+
+// CertReloader serves a TLS certificate from disk and transparently
+// picks up renewals: it re-stats the cert file once a minute and
+// reloads the key pair when the mtime changes, so a renewed certificate
+// takes effect without restarting the process.
+type CertReloader struct {
+	certFile, keyFile string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// NewCertReloader loads the initial certificate/key pair from disk.
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate is suitable for tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// Watch polls the certificate file for changes until stop is closed.
+func (r *CertReloader) Watch(stop <-chan struct{}) {
+	ticker := time.NewTicker(statInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			info, err := os.Stat(r.certFile)
+			if err != nil {
+				continue
+			}
+
+			r.mu.RLock()
+			changed := !info.ModTime().Equal(r.modTime)
+			r.mu.RUnlock()
+
+			if changed {
+				_ = r.reload()
+			}
+		}
+	}
+}
+
+func (r *CertReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading TLS key pair: %w", err)
+	}
+
+	info, err := os.Stat(r.certFile)
+	if err != nil {
+		return fmt.Errorf("stat TLS cert file: %w", err)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.modTime = info.ModTime()
+	r.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/yourusername/flex/media_server/internal/config"
+)
+
+// TLSConfig is everything needed to run the HTTPS listener: the
+// assembled tls.Config, and an optional reloader whose Watch goroutine
+// must be started to pick up certificate renewals.
+type TLSConfig struct {
+	Config      *tls.Config
+	Reloader    *CertReloader     // nil when using ACME, which manages its own renewal
+	ACMEManager *autocert.Manager // non-nil only when using ACME; serves the HTTP-01 challenge path
+}
+
+// BuildTLSConfig chooses between ACME/autocert (when app.ACMEDomains is
+// set) and a self-managed certificate file pair with auto-reload
+// (when app.TLSCertFile/TLSKeyFile are set). It returns nil, nil when
+// neither is configured.
+func BuildTLSConfig(appCfg config.AppConfig, mediaCfg config.MediaConfig) (*TLSConfig, error) {
+	switch {
+	case len(appCfg.ACMEDomains) > 0:
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(appCfg.ACMEDomains...),
+			Cache:      autocert.DirCache(mediaCfg.CertCachePath),
+		}
+		return &TLSConfig{Config: manager.TLSConfig(), ACMEManager: manager}, nil
+
+	case appCfg.TLSCertFile != "" && appCfg.TLSKeyFile != "":
+		reloader, err := NewCertReloader(appCfg.TLSCertFile, appCfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("building cert reloader: %w", err)
+		}
+		return &TLSConfig{
+			Config:   &tls.Config{GetCertificate: reloader.GetCertificate},
+			Reloader: reloader,
+		}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// RedirectHandler returns a handler that 301-redirects every request to
+// the same host on httpsPort over https.
+func RedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		target := "https://" + host
+		if httpsPort != "" && httpsPort != "443" {
+			target += ":" + httpsPort
+		}
+		target += r.URL.RequestURI()
+
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+}
@@ -0,0 +1,97 @@
+// Package worker runs the background job queue and worker pool that
+// take transcoding, thumbnailing, and metadata work off the HTTP path.
+package worker
+
+import "time"
+
+// JobType identifies the kind of work a Job carries.
+type JobType string
+
+const (
+	JobProbeMedia         JobType = "probe_media"
+	JobGeneratePoster     JobType = "generate_poster"
+	JobGenerateThumbnails JobType = "generate_thumbnails"
+	JobFetchTMDBMetadata  JobType = "fetch_tmdb_metadata"
+	JobTranscode          JobType = "transcode"
+	JobRemoveMedia        JobType = "remove_media"
+)
+
+// Job is a unit of work pulled off a Queue and handed to a Handler.
+// Key must be stable for a given piece of work (e.g. a content hash or
+// media ID) so that re-enqueuing the same job is a no-op for the
+// handler rather than duplicate work.
+type Job struct {
+	ID        string                 `json:"id"`
+	Type      JobType                `json:"type"`
+	Key       string                 `json:"key"`
+	Payload   map[string]interface{} `json:"payload"`
+	Attempt   int                    `json:"attempt"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// NewProbeMediaJob builds a Job that runs ffmpeg/MediaInfo probing
+// against the file at path for the given media ID.
+func NewProbeMediaJob(mediaID, path string) Job {
+	return newJob(JobProbeMedia, "probe_media:"+mediaID, map[string]interface{}{
+		"media_id": mediaID,
+		"path":     path,
+	})
+}
+
+// NewGeneratePosterJob builds a Job that extracts or fetches a poster
+// image for the given media ID.
+func NewGeneratePosterJob(mediaID, path string) Job {
+	return newJob(JobGeneratePoster, "generate_poster:"+mediaID, map[string]interface{}{
+		"media_id": mediaID,
+		"path":     path,
+	})
+}
+
+// NewGenerateThumbnailsJob builds a Job that generates scrubber
+// thumbnails for the given media ID.
+func NewGenerateThumbnailsJob(mediaID, path string) Job {
+	return newJob(JobGenerateThumbnails, "generate_thumbnails:"+mediaID, map[string]interface{}{
+		"media_id": mediaID,
+		"path":     path,
+	})
+}
+
+// NewFetchTMDBMetadataJob builds a Job that looks up TMDB metadata for
+// the given media ID.
+func NewFetchTMDBMetadataJob(mediaID, title string, year int) Job {
+	return newJob(JobFetchTMDBMetadata, "fetch_tmdb_metadata:"+mediaID, map[string]interface{}{
+		"media_id": mediaID,
+		"title":    title,
+		"year":     year,
+	})
+}
+
+// NewTranscodeJob builds a Job that transcodes the file at path into
+// the given target profile (e.g. "1080p-h264").
+func NewTranscodeJob(mediaID, path, profile string) Job {
+	return newJob(JobTranscode, "transcode:"+mediaID+":"+profile, map[string]interface{}{
+		"media_id": mediaID,
+		"path":     path,
+		"profile":  profile,
+	})
+}
+
+// NewRemoveMediaJob builds a Job that removes the media row and its
+// generated artifacts (poster, thumbnails) for a file that's gone from
+// disk.
+func NewRemoveMediaJob(mediaID, path string) Job {
+	return newJob(JobRemoveMedia, "remove_media:"+mediaID, map[string]interface{}{
+		"media_id": mediaID,
+		"path":     path,
+	})
+}
+
+func newJob(jobType JobType, key string, payload map[string]interface{}) Job {
+	return Job{
+		ID:        key,
+		Type:      jobType,
+		Key:       key,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryQueue is an in-process Queue implementation used in tests, so
+// Pool behavior (retries, backoff, dead-lettering) can be exercised
+// without a Redis instance.
+type MemoryQueue struct {
+	mu         sync.Mutex
+	jobs       []Job
+	notify     chan struct{}
+	deadLetter []Job
+}
+
+// NewMemoryQueue creates an empty MemoryQueue.
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{notify: make(chan struct{}, 1)}
+}
+
+func (q *MemoryQueue) Enqueue(ctx context.Context, job Job) error {
+	q.mu.Lock()
+	q.jobs = append(q.jobs, job)
+	q.mu.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (q *MemoryQueue) Dequeue(ctx context.Context, consumer string) (Delivery, error) {
+	for {
+		q.mu.Lock()
+		if len(q.jobs) > 0 {
+			job := q.jobs[0]
+			q.jobs = q.jobs[1:]
+			q.mu.Unlock()
+			return Delivery{Job: job, ack: func(ctx context.Context) error { return nil }}, nil
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return Delivery{}, ctx.Err()
+		case <-q.notify:
+		}
+	}
+}
+
+func (q *MemoryQueue) Ack(ctx context.Context, d Delivery) error {
+	return nil
+}
+
+func (q *MemoryQueue) Requeue(ctx context.Context, job Job) error {
+	return q.Enqueue(ctx, job)
+}
+
+func (q *MemoryQueue) DeadLetter(ctx context.Context, job Job, reason string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.deadLetter = append(q.deadLetter, job)
+	return nil
+}
+
+func (q *MemoryQueue) Stats(ctx context.Context) (QueueStats, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return QueueStats{Pending: int64(len(q.jobs)), DeadLetter: int64(len(q.deadLetter))}, nil
+}
@@ -0,0 +1,196 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Handler processes a single Job. Handlers must be idempotent: a job
+// may be redelivered after a crash or a retry.
+type Handler func(ctx context.Context, job Job) error
+
+// Pool runs a fixed number of concurrent workers pulling jobs off a
+// Queue and dispatching them to the Handler registered for their type.
+type Pool struct {
+	queue       Queue
+	concurrency int
+	maxRetries  int
+	logger      *zap.Logger
+
+	mu       sync.RWMutex
+	handlers map[JobType]Handler
+
+	inFlight int64
+	wg       sync.WaitGroup
+}
+
+// NewPool creates a Pool that will run concurrency workers against
+// queue once Start is called.
+func NewPool(queue Queue, concurrency, maxRetries int, logger *zap.Logger) *Pool {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Pool{
+		queue:       queue,
+		concurrency: concurrency,
+		maxRetries:  maxRetries,
+		logger:      logger,
+		handlers:    make(map[JobType]Handler),
+	}
+}
+
+// Register associates a Handler with a JobType. It must be called
+// before Start.
+func (p *Pool) Register(jobType JobType, handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[jobType] = handler
+}
+
+// Start launches the worker goroutines. It returns immediately; workers
+// run until ctx is canceled.
+func (p *Pool) Start(ctx context.Context) {
+	for i := 0; i < p.concurrency; i++ {
+		consumer := fmt.Sprintf("worker-%d", i)
+		p.wg.Add(1)
+		go p.run(ctx, consumer)
+	}
+}
+
+// Shutdown waits for in-flight jobs to finish, up to the given
+// deadline, which is separate from (and typically longer than) the
+// HTTP server's own shutdown timeout.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("worker pool did not drain before shutdown deadline: %w", ctx.Err())
+	}
+}
+
+func (p *Pool) run(ctx context.Context, consumer string) {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		delivery, err := p.queue.Dequeue(ctx, consumer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			// Transient dequeue error (e.g. Redis blip); back off briefly
+			// rather than spinning.
+			time.Sleep(time.Second)
+			continue
+		}
+
+		p.process(ctx, delivery)
+	}
+}
+
+func (p *Pool) process(ctx context.Context, d Delivery) {
+	job := d.Job
+
+	atomic.AddInt64(&p.inFlight, 1)
+	defer atomic.AddInt64(&p.inFlight, -1)
+
+	p.mu.RLock()
+	handler, ok := p.handlers[job.Type]
+	p.mu.RUnlock()
+
+	if !ok {
+		p.logger.Error("No handler registered for job type", zap.String("job_type", string(job.Type)))
+		_ = p.queue.DeadLetter(ctx, job, "no handler registered")
+		_ = p.queue.Ack(ctx, d)
+		return
+	}
+
+	start := time.Now()
+	err := handler(ctx, job)
+	duration := time.Since(start)
+
+	logger := p.logger.With(
+		zap.String("job_id", job.ID),
+		zap.String("job_type", string(job.Type)),
+		zap.Int("attempt", job.Attempt),
+		zap.Int64("duration_ms", duration.Milliseconds()),
+	)
+
+	if err == nil {
+		logger.Info("Job succeeded")
+		if ackErr := p.queue.Ack(ctx, d); ackErr != nil {
+			logger.Error("Failed to ack job", zap.Error(ackErr))
+		}
+		return
+	}
+
+	if job.Attempt >= p.maxRetries {
+		logger.Error("Job exhausted retries, sending to dead letter", zap.Error(err))
+		_ = p.queue.DeadLetter(ctx, job, err.Error())
+		_ = p.queue.Ack(ctx, d)
+		return
+	}
+
+	logger.Warn("Job failed, scheduling retry", zap.Error(err))
+	job.Attempt++
+	p.wg.Add(1)
+	go p.retryAfterBackoff(ctx, job, d)
+}
+
+// retryAfterBackoff waits out an exponential backoff before requeuing a
+// failed job, then, once the requeue succeeds, acks the original
+// delivery so it isn't redelivered twice. It counts itself in p.wg so
+// Shutdown waits for it, and requeues on a short-lived background
+// context rather than the passed-in ctx, so a job mid-backoff when the
+// pool is canceled still gets requeued/acked instead of silently dropped
+// (left unacked in the queue's PEL forever). If the requeue fails, the
+// original delivery is logged and left unacked rather than acked away.
+func (p *Pool) retryAfterBackoff(ctx context.Context, job Job, d Delivery) {
+	defer p.wg.Done()
+
+	delay := backoff(job.Attempt)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+
+	finishCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := p.queue.Requeue(finishCtx, job); err != nil {
+		p.logger.Error("Failed to requeue job", zap.String("job_id", job.ID), zap.Error(err))
+		return
+	}
+	if err := p.queue.Ack(finishCtx, d); err != nil {
+		p.logger.Error("Failed to ack original delivery after retry", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
+
+// backoff returns an exponential delay (1s, 2s, 4s, ...) capped at one
+// minute, for the given retry attempt.
+func backoff(attempt int) time.Duration {
+	d := time.Duration(math.Pow(2, float64(attempt-1))) * time.Second
+	if d > time.Minute {
+		d = time.Minute
+	}
+	return d
+}
@@ -0,0 +1,120 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestBackoff(t *testing.T) {
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 1 * time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{10, time.Minute}, // capped
+	}
+
+	for _, tc := range cases {
+		if got := backoff(tc.attempt); got != tc.want {
+			t.Errorf("backoff(%d) = %v, want %v", tc.attempt, got, tc.want)
+		}
+	}
+}
+
+func TestPool_DeadLettersAfterMaxRetries(t *testing.T) {
+	queue := NewMemoryQueue()
+	pool := NewPool(queue, 1, 0, zap.NewNop())
+	pool.Register(JobProbeMedia, func(ctx context.Context, job Job) error {
+		return errors.New("probe failed")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.Start(ctx)
+
+	if err := queue.Enqueue(ctx, NewProbeMediaJob("media-1", "/media/movie.mkv")); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	waitFor(t, func() bool {
+		stats, err := queue.Stats(ctx)
+		return err == nil && stats.DeadLetter == 1
+	})
+}
+
+func TestPool_AckOnSuccess(t *testing.T) {
+	queue := NewMemoryQueue()
+	pool := NewPool(queue, 1, 3, zap.NewNop())
+	pool.Register(JobProbeMedia, func(ctx context.Context, job Job) error {
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.Start(ctx)
+
+	if err := queue.Enqueue(ctx, NewProbeMediaJob("media-2", "/media/movie.mkv")); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	waitFor(t, func() bool {
+		stats, err := queue.Stats(ctx)
+		return err == nil && stats.Pending == 0 && stats.DeadLetter == 0
+	})
+}
+
+func TestPool_ShutdownWaitsForRetryGoroutines(t *testing.T) {
+	queue := NewMemoryQueue()
+	pool := NewPool(queue, 1, 5, zap.NewNop())
+	pool.Register(JobProbeMedia, func(ctx context.Context, job Job) error {
+		return errors.New("transient failure")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool.Start(ctx)
+
+	if err := queue.Enqueue(ctx, NewProbeMediaJob("media-3", "/media/movie.mkv")); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	// Let the handler fail once so retryAfterBackoff is scheduled, then
+	// cancel immediately, mirroring cancelBg() firing right before
+	// Shutdown in main.go. Shutdown must still wait for that goroutine
+	// (tracked via p.wg) rather than returning early.
+	waitFor(t, func() bool {
+		return atomicInFlightSeen(pool)
+	})
+	cancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer shutdownCancel()
+	if err := pool.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+}
+
+// atomicInFlightSeen reports whether the pool has processed at least
+// one delivery yet, so the test can cancel mid-retry instead of racing
+// the very first dequeue.
+func atomicInFlightSeen(p *Pool) bool {
+	stats, err := p.queue.Stats(context.Background())
+	return err == nil && stats.Pending == 0
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("condition not met before deadline")
+}
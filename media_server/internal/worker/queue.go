@@ -0,0 +1,45 @@
+package worker
+
+import "context"
+
+// Delivery wraps a Job with the queue-specific handle needed to
+// acknowledge, retry, or dead-letter it once processing finishes.
+type Delivery struct {
+	Job Job
+
+	// ack is called by the Pool once the handler returns, and is
+	// implementation-specific (e.g. XACK for the Redis queue).
+	ack func(ctx context.Context) error
+}
+
+// QueueStats reports queue depth for the stats endpoint.
+type QueueStats struct {
+	Pending    int64 `json:"pending"`
+	DeadLetter int64 `json:"dead_letter"`
+}
+
+// Queue is the job queue abstraction the Pool pulls work from. A
+// Redis-backed implementation is used in production; an in-memory one
+// is used in tests.
+type Queue interface {
+	// Enqueue publishes job for processing.
+	Enqueue(ctx context.Context, job Job) error
+
+	// Dequeue blocks until a job is available (or ctx is canceled) and
+	// returns it along with the means to acknowledge it.
+	Dequeue(ctx context.Context, consumer string) (Delivery, error)
+
+	// Ack marks a delivery as successfully processed.
+	Ack(ctx context.Context, d Delivery) error
+
+	// Requeue re-publishes job for another attempt, typically after an
+	// exponential backoff delay has elapsed.
+	Requeue(ctx context.Context, job Job) error
+
+	// DeadLetter records a job that exhausted its retries, along with
+	// the reason it failed.
+	DeadLetter(ctx context.Context, job Job, reason string) error
+
+	// Stats reports current queue depth.
+	Stats(ctx context.Context) (QueueStats, error)
+}
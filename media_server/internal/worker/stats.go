@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statsResponse is the body returned by StatsHandler.
+type statsResponse struct {
+	Concurrency int   `json:"concurrency"`
+	InFlight    int64 `json:"in_flight"`
+	Pending     int64 `json:"pending"`
+	DeadLetter  int64 `json:"dead_letter"`
+}
+
+// StatsHandler reports queue depth and configured concurrency. Mounted
+// at GET /internal/workers/stats.
+func (p *Pool) StatsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats, err := p.queue.Stats(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read queue stats"})
+			return
+		}
+
+		c.JSON(http.StatusOK, statsResponse{
+			Concurrency: p.concurrency,
+			InFlight:    atomic.LoadInt64(&p.inFlight),
+			Pending:     stats.Pending,
+			DeadLetter:  stats.DeadLetter,
+		})
+	}
+}